@@ -1,15 +1,21 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/tanuvnair/vunat-cli/internal/cli"
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
 )
 
 func main() {
 	if err := cli.Run(os.Args); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		// An interrupt is the user's doing; the exit status is enough.
+		var interrupt *exitcode.InterruptError
+		if !errors.As(err, &interrupt) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(cli.ExitCode(err))
 	}
 }
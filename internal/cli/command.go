@@ -1,9 +1,16 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+	"github.com/tanuvnair/vunat-cli/internal/suggest"
 )
 
 // Command represents a single CLI subcommand.
@@ -19,25 +26,65 @@ type Command interface {
 	Help() string
 }
 
+// Specifier is optionally implemented by commands that declare their flags
+// and positional arguments. For these the registry parses the arguments,
+// answers -h/--help with generated usage, reports usage errors consistently
+// and then calls Exec instead of Run.
+type Specifier interface {
+	Command
+	Spec() spec.Spec
+	Exec(v *spec.Values) error
+}
+
+// Hider is optionally implemented by internal commands (such as the shell
+// completion helper) that should be dispatched but not listed in help.
+type Hider interface {
+	Hidden() bool
+}
+
+// RawArgs is optionally implemented by commands that must receive their
+// arguments verbatim: global flags after the command name are not parsed.
+type RawArgs interface {
+	RawArgs() bool
+}
+
+func isHidden(c Command) bool {
+	h, ok := c.(Hider)
+	return ok && h.Hidden()
+}
+
 // ErrUsage is returned when the CLI is invoked without a command.
-var ErrUsage = fmt.Errorf("usage: vunat <command> [args]")
+var ErrUsage error = &spec.UsageError{Msg: "usage: vunat <command> [args]"}
+
+// globalFlag is an option accepted by every command, applied before dispatch.
+type globalFlag struct {
+	flag  spec.Flag
+	apply func(value string) error
+}
 
 // Registry holds registered commands and dispatches invocation to them.
 // Use NewRegistry to create a registry and Register to add commands.
 type Registry struct {
 	commands map[string]Command
+	globals  []globalFlag
+	aliases  map[string]string
+	// external resolves and lists commands that are not registered, such
+	// as plugins found on PATH.
+	external     func(name string) Command
+	listExternal func() []Command
+	// path is the command line prefix for this level, e.g. "vunat" or
+	// "vunat config" for the registry of a Group.
+	path string
 }
 
 // NewRegistry constructs a registry and optionally registers the provided commands.
 func NewRegistry(cmds ...Command) *Registry {
 	r := &Registry{
 		commands: make(map[string]Command),
+		path:     "vunat",
 	}
 	for _, c := range cmds {
-		if c == nil {
-			continue
-		}
-		r.commands[c.Name()] = c
+		r.Register(c)
 	}
 	return r
 }
@@ -47,14 +94,88 @@ func (r *Registry) Register(c Command) {
 	if c == nil {
 		return
 	}
+	if g, ok := c.(*Group); ok {
+		g.setPath(r.path + " " + g.Name())
+	}
 	r.commands[c.Name()] = c
 }
 
-// Commands returns a slice of registered commands (sorted by name).
+// AddGlobal registers a flag accepted before or after any command name, up
+// to the command's first argument.
+// Registry.Run removes it from the arguments and calls apply with its value
+// before dispatching.
+func (r *Registry) AddGlobal(f spec.Flag, apply func(value string) error) {
+	r.globals = append(r.globals, globalFlag{flag: f, apply: apply})
+}
+
+// SetAliases installs user-defined command aliases. Each alias maps a name to
+// a command line whose first word is a registered command, e.g.
+// "up" -> "start gradepoint", split into arguments like the commands of a
+// project. Aliases never shadow registered commands.
+func (r *Registry) SetAliases(aliases map[string]string) {
+	r.aliases = aliases
+}
+
+// Aliases returns the alias names (sorted) that don't collide with commands.
+func (r *Registry) Aliases() []string {
+	names := make([]string, 0, len(r.aliases))
+	for n := range r.aliases {
+		if _, isCmd := r.commands[n]; !isCmd {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetExternal installs a fallback for unknown command names: lookup returns
+// the command to run (or nil) and list enumerates the available ones for
+// help and completion. Registered commands and aliases take precedence.
+func (r *Registry) SetExternal(lookup func(name string) Command, list func() []Command) {
+	r.external = lookup
+	r.listExternal = list
+}
+
+// External returns the external commands (sorted by name) that are not
+// shadowed by a registered command or alias.
+func (r *Registry) External() []Command {
+	if r.listExternal == nil {
+		return nil
+	}
+	var out []Command
+	for _, c := range r.listExternal() {
+		_, isCmd := r.commands[c.Name()]
+		_, isAlias := r.aliases[c.Name()]
+		if !isCmd && !isAlias {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out
+}
+
+// AliasTarget returns the command line an alias expands to.
+func (r *Registry) AliasTarget(name string) string {
+	return r.aliases[name]
+}
+
+// GlobalFlags returns the registered global flags in registration order.
+func (r *Registry) GlobalFlags() []spec.Flag {
+	out := make([]spec.Flag, 0, len(r.globals))
+	for _, g := range r.globals {
+		out = append(out, g.flag)
+	}
+	return out
+}
+
+// Commands returns a slice of registered commands (sorted by name),
+// excluding hidden commands.
 func (r *Registry) Commands() []Command {
 	names := make([]string, 0, len(r.commands))
-	for n := range r.commands {
-		names = append(names, n)
+	for n, c := range r.commands {
+		if !isHidden(c) {
+			names = append(names, n)
+		}
 	}
 	sort.Strings(names)
 
@@ -68,10 +189,18 @@ func (r *Registry) Commands() []Command {
 // Run dispatches the args to the appropriate command.
 // args is expected to be os.Args (or an equivalent slice).
 // Behavior:
-// - If no subcommand is provided, returns ErrUsage or runs the "help" command if present.
-// - If a known subcommand is provided, calls its Run with the remaining args.
-// - If unknown command, returns an error indicating the unknown command.
+//   - Global flags are removed from args and applied first.
+//   - If no subcommand is provided, returns ErrUsage or runs the "help" command if present.
+//   - If a known subcommand is provided, calls its Run with the remaining args,
+//     or parses them against its Spec and calls Exec for Specifier commands.
+//   - Otherwise an alias, an external command or a unique prefix of a
+//     command/alias name is resolved; unknown or ambiguous names produce a
+//     usage error with suggestions.
 func (r *Registry) Run(args []string) error {
+	args, err := r.applyGlobals(args)
+	if err != nil {
+		return err
+	}
 	if len(args) < 2 {
 		// If a help command is registered, show help by default.
 		if helpCmd, ok := r.commands["help"]; ok {
@@ -80,29 +209,178 @@ func (r *Registry) Run(args []string) error {
 		return ErrUsage
 	}
 
-	name := args[1]
+	cmd, rest, err := r.resolve(args[1], args[2:])
+	if err != nil {
+		return err
+	}
+	return r.dispatch(cmd, rest)
+}
+
+// resolve finds the command for name, expanding aliases and unique
+// prefixes, and returns it with the arguments it should receive.
+func (r *Registry) resolve(name string, args []string) (Command, []string, error) {
 	if cmd, ok := r.commands[name]; ok {
-		return cmd.Run(args[2:])
+		return cmd, args, nil
+	}
+	if line, ok := r.aliases[name]; ok {
+		fields, err := projects.SplitArgs(line)
+		if err != nil {
+			return nil, nil, fmt.Errorf("alias %q: %w", name, err)
+		}
+		if len(fields) == 0 {
+			return nil, nil, fmt.Errorf("alias %q is empty", name)
+		}
+		cmd, ok := r.commands[fields[0]]
+		if !ok {
+			return nil, nil, fmt.Errorf("alias %q refers to unknown command %q", name, fields[0])
+		}
+		// Global flags inside the alias (e.g. "list -o names") apply too.
+		expanded, err := r.applyGlobals(append([]string{"vunat"}, fields...))
+		if err != nil {
+			return nil, nil, err
+		}
+		return cmd, append(expanded[2:], args...), nil
+	}
+	if r.external != nil {
+		if cmd := r.external(name); cmd != nil {
+			return cmd, args, nil
+		}
 	}
 
-	// Unknown command: return informative error and suggestion of available commands.
-	available := r.availableCommandNames()
-	return fmt.Errorf("unknown command: %s\n\nAvailable commands:\n%s", name, available)
+	candidates := append(r.visibleNames(), r.Aliases()...)
+	for _, c := range r.External() {
+		candidates = append(candidates, c.Name())
+	}
+	hint := "Run 'vunat help' for a list of commands."
+	if r.path != "vunat" {
+		hint = fmt.Sprintf("Run '%s --help' for a list of commands.", r.path)
+	}
+	switch matches := suggest.Prefix(name, candidates); len(matches) {
+	case 1:
+		return r.resolve(matches[0], args)
+	case 0:
+	default:
+		return nil, nil, &spec.UsageError{
+			Msg:   fmt.Sprintf("ambiguous command %q: could be %s", name, strings.Join(matches, ", ")),
+			Usage: hint,
+		}
+	}
+	return nil, nil, &spec.UsageError{
+		Msg:   fmt.Sprintf("unknown command %q%s", name, suggest.Hint(suggest.Similar(name, candidates))),
+		Usage: hint,
+	}
 }
 
-func (r *Registry) availableCommandNames() string {
-	names := make([]string, 0, len(r.commands))
-	for n := range r.commands {
-		names = append(names, n)
+// visibleNames returns the names of the commands that are not hidden.
+func (r *Registry) visibleNames() []string {
+	cmds := r.Commands()
+	names := make([]string, 0, len(cmds))
+	for _, c := range cmds {
+		names = append(names, c.Name())
 	}
-	sort.Strings(names)
-	var b strings.Builder
-	for _, n := range names {
-		if cmd := r.commands[n]; cmd != nil {
-			fmt.Fprintf(&b, "  %s\t%s\n", n, cmd.Help())
-		} else {
-			fmt.Fprintf(&b, "  %s\n", n)
+	return names
+}
+
+// dispatch runs cmd with its arguments, parsing them first for Specifier
+// commands.
+func (r *Registry) dispatch(cmd Command, args []string) error {
+	path := r.path + " " + cmd.Name()
+	if raw, ok := cmd.(RawArgs); ok && raw.RawArgs() {
+		return cmd.Run(args)
+	}
+	sc, ok := cmd.(Specifier)
+	if !ok {
+		if len(args) == 1 && (args[0] == "-h" || args[0] == "--help") {
+			fmt.Print(commandHelp(cmd, path))
+			return nil
 		}
+		return cmd.Run(args)
+	}
+	v, err := spec.Parse(path, sc.Spec(), args)
+	if errors.Is(err, spec.ErrHelp) {
+		fmt.Print(commandHelp(cmd, path))
+		return nil
 	}
-	return b.String()
+	if err != nil {
+		return err
+	}
+	return sc.Exec(v)
+}
+
+// applyGlobals removes registered global flags from args and applies their
+// values. Flags are taken from before the command's first positional
+// argument only, and from before the command name for RawArgs commands, so
+// that what is passed on to a task or plugin is left alone.
+func (r *Registry) applyGlobals(args []string) ([]string, error) {
+	if len(r.globals) == 0 {
+		return args, nil
+	}
+	out := make([]string, 0, len(args))
+	seenCommand := false
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			out = append(out, args[i:]...)
+			break
+		}
+		g := r.lookupGlobal(a)
+		if g == nil || i == 0 {
+			out = append(out, a)
+			if i == 0 || (len(a) > 1 && a[0] == '-') {
+				continue
+			}
+			if seenCommand || r.rawArgs(a) {
+				out = append(out, args[i+1:]...)
+				break
+			}
+			seenCommand = true
+			continue
+		}
+		_, value, hasValue := strings.Cut(a, "=")
+		if g.flag.Kind == spec.Bool && !hasValue {
+			value = "true"
+		} else if !hasValue {
+			if i+1 >= len(args) {
+				return nil, &spec.UsageError{Msg: fmt.Sprintf("flag --%s requires a value", g.flag.Name)}
+			}
+			i++
+			value = args[i]
+		}
+		if len(g.flag.Values) > 0 && !slices.Contains(g.flag.Values, value) {
+			return nil, &spec.UsageError{Msg: fmt.Sprintf("invalid value %q for --%s (expected one of: %s)", value, g.flag.Name, strings.Join(g.flag.Values, ", "))}
+		}
+		if err := g.apply(value); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// rawArgs reports whether name, once aliases and prefixes are expanded,
+// is a RawArgs command.
+func (r *Registry) rawArgs(name string) bool {
+	cmd, _, err := r.resolve(name, nil)
+	if err != nil {
+		return false
+	}
+	raw, ok := cmd.(RawArgs)
+	return ok && raw.RawArgs()
+}
+
+func (r *Registry) lookupGlobal(arg string) *globalFlag {
+	name, _, _ := strings.Cut(arg, "=")
+	for i := range r.globals {
+		f := r.globals[i].flag
+		if name == "--"+f.Name || (f.Short != "" && name == "-"+f.Short) {
+			return &r.globals[i]
+		}
+	}
+	return nil
+}
+
+// ExitCode maps an error returned by Run to a process exit status. See the
+// exitcode package for the statuses; errors wrapping an exited child (such
+// as a failed plugin or task) pass its status through.
+func ExitCode(err error) int {
+	return exitcode.Of(err)
 }
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+)
+
+// recorder is a command that records the arguments it receives.
+type recorder struct {
+	name string
+	raw  bool
+	got  []string
+}
+
+func (c *recorder) Name() string            { return c.name }
+func (c *recorder) Help() string            { return "" }
+func (c *recorder) RawArgs() bool           { return c.raw }
+func (c *recorder) Run(args []string) error { c.got = args; return nil }
+
+func TestGlobalFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		cmd    string
+		want   []string
+		output string
+	}{
+		{name: "before the command", args: []string{"-o", "json", "list"}, cmd: "list", want: []string{}, output: "json"},
+		{name: "after the command", args: []string{"list", "--output=names"}, cmd: "list", want: []string{}, output: "names"},
+		{name: "before the arguments", args: []string{"run", "-o", "json", "shop", "lint"}, cmd: "run", want: []string{"shop", "lint"}, output: "json"},
+		{name: "among the arguments", args: []string{"run", "shop", "lint", "-o", "json"}, cmd: "run", want: []string{"shop", "lint", "-o", "json"}},
+		{name: "after --", args: []string{"run", "--", "-o", "json"}, cmd: "run", want: []string{"--", "-o", "json"}},
+		{name: "other flags", args: []string{"run", "--yes", "-o", "json"}, cmd: "run", want: []string{"--yes"}, output: "json"},
+		{name: "raw command", args: []string{"-o", "json", "plugin", "-o", "x"}, cmd: "plugin", want: []string{"-o", "x"}, output: "json"},
+		{name: "raw command by prefix", args: []string{"plug", "-o", "x"}, cmd: "plugin", want: []string{"-o", "x"}},
+		{name: "raw command by alias", args: []string{"p", "-o", "x"}, cmd: "plugin", want: []string{"--verbose", "-o", "x"}},
+		{name: "external raw command", args: []string{"ext", "-o", "x"}, cmd: "ext", want: []string{"-o", "x"}},
+		{name: "quoted alias", args: []string{"lint", "extra"}, cmd: "run", want: []string{"shop", "go vet ./...", "extra"}, output: "json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmds := map[string]*recorder{
+				"list":   {name: "list"},
+				"run":    {name: "run"},
+				"plugin": {name: "plugin", raw: true},
+				"ext":    {name: "ext", raw: true},
+			}
+			r := NewRegistry(cmds["list"], cmds["run"], cmds["plugin"])
+			r.SetAliases(map[string]string{"p": "plugin --verbose", "lint": `run -o json shop "go vet ./..."`})
+			r.SetExternal(func(name string) Command {
+				if name == "ext" {
+					return cmds["ext"]
+				}
+				return nil
+			}, nil)
+			output := ""
+			r.AddGlobal(spec.Flag{Name: "output", Short: "o"}, func(v string) error { output = v; return nil })
+
+			if err := r.Run(append([]string{"vunat"}, tt.args...)); err != nil {
+				t.Fatal(err)
+			}
+			if got := cmds[tt.cmd].got; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s got %q, want %q", tt.cmd, got, tt.want)
+			}
+			if output != tt.output {
+				t.Errorf("output = %q, want %q", output, tt.output)
+			}
+		})
+	}
+}
+
+func TestAliasQuoting(t *testing.T) {
+	run := &recorder{name: "run"}
+	r := NewRegistry(run)
+	r.SetAliases(map[string]string{"bad": "run 'shop"})
+	err := r.Run([]string{"vunat", "bad"})
+	if err == nil || !strings.Contains(err.Error(), `alias "bad"`) {
+		t.Fatalf("Run() = %v, want an error naming the alias", err)
+	}
+	if run.got != nil {
+		t.Errorf("run got %q, want it not to run", run.got)
+	}
+}
+
+// specCommand is a Specifier and Describer taking optional arguments.
+type specCommand struct {
+	name string
+	got  []string
+	runs int
+}
+
+func (c *specCommand) Name() string            { return c.name }
+func (c *specCommand) Help() string            { return "Open it" }
+func (c *specCommand) Description() string     { return "Opens the file." }
+func (c *specCommand) Examples() []string      { return []string{"vunat " + c.name} }
+func (c *specCommand) Run(args []string) error { return spec.Run(c, args) }
+func (c *specCommand) Spec() spec.Spec {
+	return spec.Spec{Args: []spec.Arg{{Name: "file", Optional: true, Variadic: true}}}
+}
+func (c *specCommand) Exec(v *spec.Values) error { c.got = v.Args; c.runs++; return nil }
+
+func TestRenamed(t *testing.T) {
+	cmd := &specCommand{name: "config"}
+	edit := Renamed("edit", cmd)
+	group := NewGroup("config", "Open or locate the config file", edit)
+	group.Default = edit
+	r := NewRegistry(group)
+
+	if err := r.Run([]string{"vunat", "config", "edit", "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Run([]string{"vunat", "config"}); err != nil {
+		t.Fatal(err)
+	}
+	if cmd.runs != 2 || len(cmd.got) != 0 {
+		t.Errorf("runs = %d, last args %q; want 2 runs, the last without arguments", cmd.runs, cmd.got)
+	}
+	if cmd.Name() != "config" || edit.Name() != "edit" {
+		t.Errorf("names = %q, %q; want config, edit", cmd.Name(), edit.Name())
+	}
+	help := commandHelp(edit, "vunat config edit")
+	for _, want := range []string{"vunat config edit - Open it", "Opens the file.", "usage: vunat config edit [<file>...]", "  vunat config\n"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("help is missing %q:\n%s", want, help)
+		}
+	}
+}
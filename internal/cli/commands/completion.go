@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/completion"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// CompletionCommand prints a shell completion script.
+//
+// Usage: vunat completion <shell>
+type CompletionCommand struct{}
+
+// NewCompletionCommand constructs a CompletionCommand.
+func NewCompletionCommand() *CompletionCommand {
+	return &CompletionCommand{}
+}
+
+func (c *CompletionCommand) Name() string { return "completion" }
+func (c *CompletionCommand) Help() string { return "Print a shell completion script" }
+
+func (c *CompletionCommand) Description() string {
+	return "Prints a completion script for bash, zsh, fish or PowerShell. The script\n" +
+		"completes commands, flags and project names by asking vunat itself, so it\n" +
+		"stays current as commands and projects are added."
+}
+
+func (c *CompletionCommand) Examples() []string {
+	return []string{
+		"source <(vunat completion bash)  # add to ~/.bashrc",
+		"source <(vunat completion zsh)   # add to ~/.zshrc",
+		"vunat completion fish > ~/.config/fish/completions/vunat.fish",
+		"vunat completion powershell | Out-String | Invoke-Expression  # add to $PROFILE",
+	}
+}
+
+func (c *CompletionCommand) Spec() spec.Spec {
+	return spec.Spec{Args: []spec.Arg{{
+		Name:     "shell",
+		Usage:    "bash, zsh, fish or powershell",
+		Complete: func([]string) []string { return completion.Shells },
+	}}}
+}
+
+func (c *CompletionCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *CompletionCommand) Exec(v *spec.Values) error {
+	script, err := completion.Script(v.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Print(script)
+	return nil
+}
+
+// CompleteCommand is the hidden `__complete` command called by the completion
+// scripts. It prints candidates for the word under the cursor using the
+// provided completer (the CLI registry), one per line.
+type CompleteCommand struct {
+	Completer func(words []string) []string
+}
+
+// NewCompleteCommand constructs a CompleteCommand backed by completer.
+func NewCompleteCommand(completer func(words []string) []string) *CompleteCommand {
+	return &CompleteCommand{Completer: completer}
+}
+
+func (c *CompleteCommand) Name() string { return "__complete" }
+func (c *CompleteCommand) Help() string { return "Print shell completion candidates" }
+func (c *CompleteCommand) Hidden() bool { return true }
+
+// RawArgs makes the registry pass the words being completed through
+// untouched, including global flags such as --output.
+func (c *CompleteCommand) RawArgs() bool { return true }
+
+func (c *CompleteCommand) Run(args []string) error {
+	if n := len(args); n > 0 && args[n-1] == `""` {
+		args[n-1] = ""
+	}
+	for _, candidate := range c.Completer(args) {
+		fmt.Println(candidate)
+	}
+	return nil
+}
+
+// completeProjects returns the registered project names for completion.
+func completeProjects([]string) []string {
+	return sortedProjectNames(projects.GetAll())
+}
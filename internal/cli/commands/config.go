@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
 	"github.com/tanuvnair/vunat-cli/internal/config"
 	"github.com/tanuvnair/vunat-cli/internal/launcher"
 )
@@ -13,7 +14,7 @@ import (
 // ConfigCommand implements the 'config' subcommand.
 //
 // Behavior:
-// - Validates there are no extra args (usage: `vunat config`).
+// - Accepts no arguments (usage: `vunat config`).
 // - Ensures the config file exists via config.Manager.Ensure().
 // - If $EDITOR is set, launches the editor and waits for it to exit (so the user can edit).
 // - Otherwise uses the injected launcher.Launcher to open the config file with the platform default.
@@ -44,12 +45,22 @@ func (c *ConfigCommand) Help() string {
 	return "Open the config file in your editor"
 }
 
-func (c *ConfigCommand) Run(args []string) error {
-	// No args expected
-	if len(args) != 0 {
-		return fmt.Errorf("usage: vunat config")
-	}
+func (c *ConfigCommand) Description() string {
+	return "Creates ~/.vunat/config.json if it does not exist and opens it. If $EDITOR is set\n" +
+		"it is run in the terminal and vunat waits for it to exit; otherwise the file is\n" +
+		"opened with the platform default application (xdg-open, open or start)."
+}
+
+func (c *ConfigCommand) Examples() []string {
+	return []string{"vunat config", "EDITOR=vim vunat config"}
+}
+
+// Spec declares that config takes no arguments.
+func (c *ConfigCommand) Spec() spec.Spec { return spec.Spec{} }
+
+func (c *ConfigCommand) Run(args []string) error { return spec.Run(c, args) }
 
+func (c *ConfigCommand) Exec(v *spec.Values) error {
 	if c.cfg == nil {
 		return fmt.Errorf("config manager not provided")
 	}
@@ -97,3 +108,35 @@ func (c *ConfigCommand) Run(args []string) error {
 
 	return nil
 }
+
+// ConfigPathCommand implements 'config path', which prints the location of
+// the config file (without creating it) for use in scripts.
+type ConfigPathCommand struct {
+	cfg config.Manager
+}
+
+// NewConfigPathCommand constructs a ConfigPathCommand.
+func NewConfigPathCommand(cfg config.Manager) *ConfigPathCommand {
+	return &ConfigPathCommand{cfg: cfg}
+}
+
+func (c *ConfigPathCommand) Name() string { return "path" }
+func (c *ConfigPathCommand) Help() string { return "Print the path of the config file" }
+
+func (c *ConfigPathCommand) Examples() []string {
+	return []string{"vunat config path", "cat \"$(vunat config path)\""}
+}
+
+func (c *ConfigPathCommand) Description() string { return "" }
+
+func (c *ConfigPathCommand) Spec() spec.Spec { return spec.Spec{} }
+
+func (c *ConfigPathCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *ConfigPathCommand) Exec(v *spec.Values) error {
+	if c.cfg == nil {
+		return fmt.Errorf("config manager not provided")
+	}
+	fmt.Println(c.cfg.Path())
+	return nil
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 2
+
+// writeDiff prints a line-based diff between before and after: removed lines
+// are prefixed with "-", added lines with "+" and nearby unchanged lines with
+// a space. Long runs of unchanged lines are collapsed to "...".
+func writeDiff(w io.Writer, before, after string) {
+	a := splitLines(before)
+	b := splitLines(after)
+
+	// Longest common subsequence table, filled from the end.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type op struct {
+		kind byte
+		line string
+	}
+	var ops []op
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+
+	// Only print unchanged lines that are close to a change.
+	near := func(k int) bool {
+		for d := -diffContext; d <= diffContext; d++ {
+			if n := k + d; n >= 0 && n < len(ops) && ops[n].kind != ' ' {
+				return true
+			}
+		}
+		return false
+	}
+	skipped := false
+	for k, o := range ops {
+		if o.kind == ' ' && !near(k) {
+			if !skipped {
+				fmt.Fprintln(w, "  ...")
+				skipped = true
+			}
+			continue
+		}
+		skipped = false
+		fmt.Fprintf(w, "%c %s\n", o.kind, o.line)
+	}
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
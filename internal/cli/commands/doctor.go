@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/doctor"
+	"github.com/tanuvnair/vunat-cli/internal/output"
+)
+
+// DoctorCommand diagnoses common environment problems that make `vunat start`
+// fail: unreadable config, missing directories or executables, busy ports
+// and an unusable editor/install setup.
+//
+// Usage: vunat doctor [project_name]
+type DoctorCommand struct {
+	Checker *doctor.Checker
+	Printer *output.Printer
+	Out     io.Writer
+}
+
+// NewDoctorCommand constructs a DoctorCommand using checker and rendering
+// structured output through p.
+func NewDoctorCommand(checker *doctor.Checker, p *output.Printer) *DoctorCommand {
+	return &DoctorCommand{Checker: checker, Printer: p, Out: os.Stdout}
+}
+
+func (c *DoctorCommand) Name() string { return "doctor" }
+func (c *DoctorCommand) Help() string { return "Check the environment for common problems" }
+
+func (c *DoctorCommand) Description() string {
+	return "Runs checks that commonly explain a failing `vunat start`: the config file is\n" +
+		"readable, every absolutePath exists, each command's executable can be found,\n" +
+		"declared ports are free, $EDITOR or the platform opener is available for\n" +
+		"`vunat config`, and vunat is installed on PATH. Exits with status 1 if any\n" +
+		"check fails."
+}
+
+func (c *DoctorCommand) Examples() []string {
+	return []string{"vunat doctor", "vunat doctor gradepoint --output json"}
+}
+
+func (c *DoctorCommand) Spec() spec.Spec {
+	return spec.Spec{Args: []spec.Arg{{Name: "project_name", Optional: true, Usage: "only check this project", Complete: completeProjects}}}
+}
+
+func (c *DoctorCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *DoctorCommand) Exec(v *spec.Values) error {
+	results := c.Checker.Run(v.Arg(0))
+	var warns, fails int
+	for _, r := range results {
+		switch r.Status {
+		case doctor.Warn:
+			warns++
+		case doctor.Fail:
+			fails++
+		}
+	}
+
+	if c.Printer.Structured() {
+		if err := c.Printer.Print(doctorResults(results)); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			fmt.Fprintf(c.Out, "[%s] %-10s %s\n", strings.ToUpper(r.Status.String()), r.Check, r.Detail)
+		}
+		fmt.Fprintf(c.Out, "\n%d check(s): %d passed, %d warning(s), %d failed\n", len(results), len(results)-warns-fails, warns, fails)
+	}
+	if fails > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", fails)
+	}
+	return nil
+}
+
+// doctorResults implements output.Tabular. The names format lists the checks
+// that did not pass, one per line.
+type doctorResults []doctor.Result
+
+func (r doctorResults) Header() []string { return []string{"STATUS", "CHECK", "DETAIL"} }
+
+func (r doctorResults) Rows() [][]string {
+	rows := make([][]string, 0, len(r))
+	for _, res := range r {
+		rows = append(rows, []string{res.Status.String(), res.Check, res.Detail})
+	}
+	return rows
+}
+
+func (r doctorResults) Names() []string {
+	var names []string
+	for _, res := range r {
+		if res.Status != doctor.Pass {
+			names = append(names, res.Check)
+		}
+	}
+	return names
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"io"
+	"os"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/exporter"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// ExportCommand renders a registered project as a Procfile, docker-compose
+// file, shell script or systemd units so it can be run without vunat.
+//
+// Usage: vunat export <project_name> [--format procfile|compose|sh|systemd]
+type ExportCommand struct {
+	Out io.Writer
+}
+
+// NewExportCommand constructs an ExportCommand writing to stdout.
+func NewExportCommand() *ExportCommand {
+	return &ExportCommand{Out: os.Stdout}
+}
+
+func (c *ExportCommand) Name() string { return "export" }
+func (c *ExportCommand) Help() string {
+	return "Print a project as a Procfile, compose file, shell script or systemd units"
+}
+
+func (c *ExportCommand) Description() string {
+	return "Renders a project's groups, directories, commands and environment in a format\n" +
+		"that runs without vunat and writes it to stdout. Group order is kept: compose\n" +
+		"and systemd output express it as dependencies on the previous group. Compose\n" +
+		"services run their command in an image guessed from it (node:lts for npm,\n" +
+		"golang:1 for go, alpine:3 otherwise) with their directory mounted at /app."
+}
+
+func (c *ExportCommand) Examples() []string {
+	return []string{
+		"vunat export gradepoint --format compose > docker-compose.yml",
+		"vunat export gradepoint -f sh > start.sh",
+	}
+}
+
+func (c *ExportCommand) Spec() spec.Spec {
+	return spec.Spec{
+		Flags: []spec.Flag{
+			{Name: "format", Short: "f", Default: "sh", Values: exporter.Formats, Usage: "output format"},
+		},
+		Args: []spec.Arg{{Name: "project_name", Usage: "registered project to export", Complete: completeProjects}},
+	}
+}
+
+func (c *ExportCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *ExportCommand) Exec(v *spec.Values) error {
+	name, err := projects.Resolve(v.Arg(0))
+	if err != nil {
+		return err
+	}
+	proj, err := projects.Get(name)
+	if err != nil {
+		return err
+	}
+	return exporter.Export(c.Out, name, proj, v.String("format"))
+}
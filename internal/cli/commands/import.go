@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/config"
+	"github.com/tanuvnair/vunat-cli/internal/importer"
+)
+
+// ImportCommand generates a project from process definitions that already
+// exist in a directory: a Procfile, docker-compose services, package.json
+// scripts or Makefile targets.
+//
+// Usage: vunat import <dir> [--name <project>] [--from <source>] [--yes]
+type ImportCommand struct {
+	cfg config.Manager
+	In  io.Reader
+	Out io.Writer
+}
+
+// NewImportCommand constructs an ImportCommand that writes through cfg.
+func NewImportCommand(cfg config.Manager) *ImportCommand {
+	return &ImportCommand{cfg: cfg, In: os.Stdin, Out: os.Stdout}
+}
+
+func (c *ImportCommand) Name() string { return "import" }
+func (c *ImportCommand) Help() string {
+	return "Create a project from a Procfile, compose file, package.json or Makefile"
+}
+
+func (c *ImportCommand) Description() string {
+	return "Looks for process definitions in <dir> and turns them into a project:\n" +
+		"  - Procfile: one group per process\n" +
+		"  - compose.yaml / docker-compose.yml: one group per service with its command,\n" +
+		"    build context, environment and depends_on ordering\n" +
+		"  - package.json: the dev/start/serve/watch script, run with the detected package manager\n" +
+		"  - Makefile: the dev/run/serve/start/watch target, or the default target\n" +
+		"The first file found (in that order) is used unless --from is given. The\n" +
+		"change to the config file is shown as a diff before it is written."
+}
+
+func (c *ImportCommand) Examples() []string {
+	return []string{
+		"vunat import ~/code/gradepoint",
+		"vunat import . --from compose --name stack --yes",
+	}
+}
+
+func (c *ImportCommand) Spec() spec.Spec {
+	sources := make([]string, 0, len(importer.Sources))
+	for _, src := range importer.Sources {
+		sources = append(sources, src.Name)
+	}
+	return spec.Spec{
+		Flags: []spec.Flag{
+			{Name: "name", Short: "n", Placeholder: "project", Usage: "project name (defaults to the directory name)"},
+			{Name: "from", Values: sources, Usage: "only import from this source"},
+			{Name: "yes", Short: "y", Kind: spec.Bool, Usage: "write without asking for confirmation"},
+		},
+		Args: []spec.Arg{{Name: "dir", Usage: "directory containing the process definitions", Path: true}},
+	}
+}
+
+func (c *ImportCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *ImportCommand) Exec(v *spec.Values) error {
+	res, err := importer.Import(v.Arg(0), v.String("from"))
+	if err != nil {
+		return err
+	}
+	name := v.String("name")
+	if name == "" {
+		name = filepath.Base(filepath.Dir(res.File))
+	}
+
+	fmt.Fprintf(c.Out, "Imported %d group(s) from %s\n\n", len(res.Project.Groups), res.File)
+	_, err = saveProject(c.cfg, name, res.Project, v.Bool("yes"), newPrompter(c.In, c.Out))
+	return err
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/config"
+	"github.com/tanuvnair/vunat-cli/internal/importer"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// InitCommand creates a project for the current directory. It inspects the
+// directory and its immediate subdirectories (go.mod, package.json,
+// Cargo.toml, Procfile, compose files, Makefile), proposes command groups and
+// lets the user adjust names, paths and commands before saving.
+//
+// Usage: vunat init [--name <project>] [--yes]
+type InitCommand struct {
+	cfg config.Manager
+	In  io.Reader
+	Out io.Writer
+	// Dir is the directory to inspect; empty means the working directory.
+	Dir string
+}
+
+// NewInitCommand constructs an InitCommand that writes through cfg.
+func NewInitCommand(cfg config.Manager) *InitCommand {
+	return &InitCommand{cfg: cfg, In: os.Stdin, Out: os.Stdout}
+}
+
+func (c *InitCommand) Name() string { return "init" }
+func (c *InitCommand) Help() string {
+	return "Create a project for the current directory interactively"
+}
+
+func (c *InitCommand) Description() string {
+	return "Inspects the current directory and its immediate subdirectories for go.mod,\n" +
+		"package.json, Cargo.toml, Procfile, compose and Makefile files and proposes a\n" +
+		"group for each. You can accept, skip or edit every group and add your own\n" +
+		"before the config change is shown and confirmed. --yes accepts the proposals\n" +
+		"and writes them without prompting."
+}
+
+func (c *InitCommand) Examples() []string {
+	return []string{"vunat init", "vunat init --name api --yes"}
+}
+
+func (c *InitCommand) Spec() spec.Spec {
+	return spec.Spec{
+		Flags: []spec.Flag{
+			{Name: "name", Short: "n", Placeholder: "project", Usage: "project name (defaults to the directory name)"},
+			{Name: "yes", Short: "y", Kind: spec.Bool, Usage: "accept all proposals and write without prompting"},
+		},
+	}
+}
+
+func (c *InitCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *InitCommand) Exec(v *spec.Values) error {
+	var err error
+	name, yes := v.String("name"), v.Bool("yes")
+	dir := c.Dir
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return fmt.Errorf("failed to determine working directory: %w", err)
+		}
+	}
+	proposed, err := importer.Detect(dir)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", dir, err)
+	}
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	if yes {
+		if len(proposed) == 0 {
+			return fmt.Errorf("nothing to run detected in %s; run `vunat init` without --yes to enter commands", dir)
+		}
+		_, err = saveProject(c.cfg, name, projects.Project{Groups: proposed}, true, newPrompter(c.In, c.Out))
+		return err
+	}
+
+	p := newPrompter(c.In, c.Out)
+	if name, err = p.Ask("Project name", name); err != nil {
+		return err
+	}
+
+	var groups []projects.CommandGroup
+	if len(proposed) == 0 {
+		fmt.Fprintf(c.Out, "No runnable commands detected in %s.\n", dir)
+	} else {
+		fmt.Fprintf(c.Out, "Detected %d group(s):\n", len(proposed))
+		for _, g := range proposed {
+			fmt.Fprintf(c.Out, "  [%s] in %s: %s\n", g.Name, g.AbsolutePath, strings.Join(projects.CommandLines(g.Commands), "; "))
+		}
+		fmt.Fprintln(c.Out)
+		for _, g := range proposed {
+			ok, err := p.Confirm(fmt.Sprintf("Include group %q?", g.Name), true)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if g, err = c.editGroup(p, g); err != nil {
+				return err
+			}
+			groups = append(groups, g)
+		}
+	}
+
+	for {
+		more, err := p.Confirm("Add another group?", len(groups) == 0)
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+		g, err := c.editGroup(p, projects.CommandGroup{Name: filepath.Base(dir), AbsolutePath: dir})
+		if err != nil {
+			return err
+		}
+		if len(g.Commands) > 0 {
+			groups = append(groups, g)
+		}
+	}
+
+	if len(groups) == 0 {
+		fmt.Fprintln(c.Out, "No groups selected; nothing to save.")
+		return nil
+	}
+	fmt.Fprintln(c.Out)
+	_, err = saveProject(c.cfg, name, projects.Project{Groups: groups}, false, p)
+	return err
+}
+
+// editGroup prompts for a group's name, directory and commands using the
+// current values as defaults. Commands are entered one per line, so they may
+// contain ";"; a command kept from the defaults keeps its settings.
+func (c *InitCommand) editGroup(p *prompter, g projects.CommandGroup) (projects.CommandGroup, error) {
+	var err error
+	if g.Name, err = p.Ask("  Group name", g.Name); err != nil {
+		return g, err
+	}
+	if g.AbsolutePath, err = p.Ask("  Directory", g.AbsolutePath); err != nil {
+		return g, err
+	}
+	if abs, err := filepath.Abs(g.AbsolutePath); err == nil && g.AbsolutePath != "" {
+		g.AbsolutePath = abs
+	}
+	lines, err := p.AskLines("  Commands", projects.CommandLines(g.Commands))
+	if err != nil {
+		return g, err
+	}
+	old := g.Commands
+	g.Commands = nil
+	for _, line := range lines {
+		cmd := projects.Command{Command: line}
+		for _, o := range old {
+			if o.Command == line {
+				cmd = o
+				break
+			}
+		}
+		g.Commands = append(g.Commands, cmd)
+	}
+	return g, nil
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tanuvnair/vunat-cli/internal/config"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+func TestInitInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		procfile string
+		input    string
+		// want is the commands of the saved project, empty when init must
+		// fail at the end of the input.
+		want []string
+	}{
+		{name: "empty input", input: ""},
+		{name: "input ends at a prompt", input: "demo\n\nweb\n\nnpm run dev\n\n"},
+		{name: "input ends in the command list", input: "demo\n\nweb\n\nnpm run dev\n"},
+		{name: "complete answers", input: "demo\n\nweb\n\nnpm run dev\n\nn\ny\n", want: []string{"npm run dev"}},
+		{
+			name:  "commands with semicolons",
+			input: "demo\n\nweb\n\nsh -c 'make; make test'\nnpm run dev\n\nn\ny\n",
+			want:  []string{"sh -c 'make; make test'", "npm run dev"},
+		},
+		{name: "detected commands kept", procfile: "web: npm start\nworker: npm run worker\n", input: "demo\ny\n\n\n\ny\n\n\n\nn\ny\n", want: []string{"npm start", "npm run worker"}},
+		{name: "detected commands replaced", procfile: "web: npm start\n", input: "demo\ny\n\n\nnpm run dev\n\nn\ny\n", want: []string{"npm run dev"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewFSManager(filepath.Join(t.TempDir(), "config.json"))
+			c := NewInitCommand(cfg)
+			c.Dir = t.TempDir()
+			if tt.procfile != "" {
+				if err := os.WriteFile(filepath.Join(c.Dir, "Procfile"), []byte(tt.procfile), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c.In = strings.NewReader(tt.input)
+			c.Out = io.Discard
+
+			done := make(chan error, 1)
+			go func() { done <- c.Run(nil) }()
+			var err error
+			select {
+			case err = <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("init kept prompting after the end of the input")
+			}
+
+			if tt.want == nil {
+				if !errors.Is(err, io.EOF) {
+					t.Fatalf("err = %v, want io.EOF", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := cfg.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			conf, err := projects.Parse(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, g := range conf.Projects["demo"].Groups {
+				got = append(got, projects.CommandLines(g.Commands)...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("saved commands = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
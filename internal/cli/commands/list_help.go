@@ -2,27 +2,60 @@ package commands
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/output"
 	"github.com/tanuvnair/vunat-cli/internal/projects"
 )
 
-// ListCommand lists all registered projects from the config.
-type ListCommand struct{}
+// ListCommand lists all registered projects from the config, sorted by name.
+// With a structured --output format it prints the full project definitions.
+type ListCommand struct {
+	Printer *output.Printer
+}
 
-// NewListCommand constructs a ListCommand.
-func NewListCommand() *ListCommand {
-	return &ListCommand{}
+// NewListCommand constructs a ListCommand rendering through p.
+func NewListCommand(p *output.Printer) *ListCommand {
+	return &ListCommand{Printer: p}
 }
 
 func (c *ListCommand) Name() string { return "list" }
 func (c *ListCommand) Help() string { return "List all registered projects" }
 
-func (c *ListCommand) Run(args []string) error {
-	if len(args) != 0 {
-		return fmt.Errorf("usage: vunat list")
+func (c *ListCommand) Description() string {
+	return "Prints every project in ~/.vunat/config.json, sorted by name, with its groups,\n" +
+		"directories and commands. With the global --output option the full project\n" +
+		"definitions are printed as JSON or YAML, as a table, or as bare project names."
+}
+
+func (c *ListCommand) Examples() []string {
+	return []string{
+		"vunat list",
+		"vunat list --output json",
+		"vunat list -o names  # one project name per line, for scripts",
 	}
+}
+
+func (c *ListCommand) Spec() spec.Spec         { return spec.Spec{} }
+func (c *ListCommand) Run(args []string) error { return spec.Run(c, args) }
 
+func (c *ListCommand) Exec(v *spec.Values) error {
 	allProjects := projects.GetAll()
+	names := make([]string, 0, len(allProjects))
+	for name := range allProjects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if c.Printer.Structured() {
+		list := make(projectList, 0, len(names))
+		for _, name := range names {
+			list = append(list, newProjectView(name, allProjects[name]))
+		}
+		return c.Printer.Print(list)
+	}
 
 	if len(allProjects) == 0 {
 		fmt.Println("No projects registered. Add projects to ~/.vunat/config.json")
@@ -30,37 +63,143 @@ func (c *ListCommand) Run(args []string) error {
 	}
 
 	fmt.Println("Registered projects:")
-	for name, project := range allProjects {
+	for _, name := range names {
 		fmt.Printf("  %s\n", name)
-		for _, group := range project {
+		proj := allProjects[name]
+		for _, group := range proj.Groups {
 			fmt.Printf("    [%s] in %s\n", group.Name, group.AbsolutePath)
 			for _, cmd := range group.Commands {
-				fmt.Printf("      → %s\n", cmd)
+				var tags []string
+				if cmd.Oneshot() {
+					tags = append(tags, "oneshot")
+				}
+				if cmd.Watch != nil {
+					tags = append(tags, "watch")
+				}
+				if cmd.TTY {
+					tags = append(tags, "tty")
+				}
+				if cmd.Stdin {
+					tags = append(tags, "stdin")
+				}
+				if len(tags) > 0 {
+					fmt.Printf("      → %s (%s)\n", cmd.Command, strings.Join(tags, ", "))
+				} else {
+					fmt.Printf("      → %s\n", cmd.Command)
+				}
+			}
+		}
+		if len(proj.Tasks) > 0 {
+			fmt.Printf("    tasks:\n")
+			for _, task := range proj.TaskNames() {
+				fmt.Printf("      %s: %s\n", task, proj.Tasks[task].Command)
 			}
 		}
 	}
 	return nil
 }
 
+// projectView is the machine-readable form of a registered project: its
+// config entry with the name added, always written as an object.
+type projectView struct {
+	Name   string                   `json:"name"`
+	Groups []projects.CommandGroup  `json:"groups"`
+	Tasks  map[string]projects.Task `json:"tasks,omitempty"`
+	projects.Hooks
+	projects.Policy
+}
+
+func newProjectView(name string, proj projects.Project) projectView {
+	return projectView{Name: name, Groups: proj.Groups, Tasks: proj.Tasks, Hooks: proj.Hooks, Policy: proj.Policy}
+}
+
+// projectList implements output.Tabular with one table row per command.
+type projectList []projectView
+
+func (l projectList) Header() []string { return []string{"PROJECT", "GROUP", "PATH", "COMMAND"} }
+
+func (l projectList) Rows() [][]string {
+	var rows [][]string
+	for _, p := range l {
+		for _, g := range p.Groups {
+			for _, cmd := range g.Commands {
+				rows = append(rows, []string{p.Name, g.Name, g.AbsolutePath, cmd.Command})
+			}
+		}
+	}
+	return rows
+}
+
+func (l projectList) Names() []string {
+	names := make([]string, 0, len(l))
+	for _, p := range l {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
 // HelpCommand prints dynamic help text supplied by the CLI registry.
 // The commands package purposely avoids importing the registry to prevent import cycles;
 // instead, the registry should construct a HelpCommand and pass a provider function.
 type HelpCommand struct {
 	// Provider returns the help text to print. If nil, HelpCommand falls back to a simple message.
 	Provider func() string
+	// Detail returns the detailed help for a command path such as ["start"]
+	// or ["config", "edit"], as shown by `vunat help <command>...`. If nil,
+	// only the overview is available.
+	Detail func(path []string) (string, error)
+	// Names optionally lists the command names at a path (top-level for an
+	// empty path) for shell completion.
+	Names func(path []string) []string
 }
 
-// NewHelpCommand constructs a HelpCommand. Pass a provider that returns the full help text.
-func NewHelpCommand(provider func() string) *HelpCommand {
-	return &HelpCommand{Provider: provider}
+// NewHelpCommand constructs a HelpCommand. Pass a provider that returns the full help text
+// and a detail function that returns the help for one command.
+func NewHelpCommand(provider func() string, detail func(path []string) (string, error)) *HelpCommand {
+	return &HelpCommand{Provider: provider, Detail: detail}
 }
 
 func (h *HelpCommand) Name() string { return "help" }
 func (h *HelpCommand) Help() string { return "Show this help message" }
 
-func (h *HelpCommand) Run(args []string) error {
-	if len(args) != 0 {
-		return fmt.Errorf("usage: vunat help")
+func (h *HelpCommand) Description() string {
+	return "Without arguments, lists every command with a one-line summary.\n" +
+		"With a command name, shows that command's description, usage, options and examples;\n" +
+		"name a subcommand after its group (e.g. `vunat help config path`)."
+}
+
+func (h *HelpCommand) Examples() []string {
+	return []string{"vunat help", "vunat help start", "vunat help config path"}
+}
+
+func (h *HelpCommand) Spec() spec.Spec {
+	return spec.Spec{Args: []spec.Arg{{
+		Name:     "command",
+		Optional: true,
+		Variadic: true,
+		Usage:    "show detailed help for this command (or subcommand path)",
+		Complete: func(path []string) []string {
+			if h.Names == nil {
+				return nil
+			}
+			return h.Names(path)
+		},
+	}}}
+}
+
+func (h *HelpCommand) Run(args []string) error { return spec.Run(h, args) }
+
+func (h *HelpCommand) Exec(v *spec.Values) error {
+	if len(v.Args) > 0 {
+		if h.Detail == nil {
+			return fmt.Errorf("no detailed help available for %s", strings.Join(v.Args, " "))
+		}
+		text, err := h.Detail(v.Args)
+		if err != nil {
+			return err
+		}
+		fmt.Print(text)
+		return nil
 	}
 	if h.Provider == nil {
 		// Basic fallback help
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tanuvnair/vunat-cli/internal/output"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+func TestProjectViewOutput(t *testing.T) {
+	proj := projects.Project{
+		Groups: []projects.CommandGroup{{Name: "web", AbsolutePath: "/srv/shop", Commands: projects.NewCommands("npm start")}},
+		Hooks:  projects.Hooks{Before: []string{"docker compose up -d"}, HookFailure: projects.HookContinue},
+		Policy: projects.Policy{OnFailure: "continue", OnExit: "stop-all"},
+	}
+	tests := []struct {
+		format output.Format
+		want   []string
+	}{
+		{format: output.JSON, want: []string{`"name": "shop"`, `"before": [`, `"docker compose up -d"`, `"hookFailure": "continue"`, `"onFailure": "continue"`, `"onExit": "stop-all"`}},
+		{format: output.YAML, want: []string{"- name: shop", "  before:\n  - docker compose up -d", "  hookFailure: continue", "  onFailure: continue", "  onExit: stop-all"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var b strings.Builder
+			p := &output.Printer{Format: tt.format, Out: &b}
+			if err := p.Print(projectList{newProjectView("shop", proj)}); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("output is missing %q:\n%s", want, b.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/plugin"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// PluginCommand runs an external `vunat-<name>` executable. The plugin gets
+// the remaining arguments verbatim and these environment variables:
+//
+//	VUNAT_BIN           path of the running vunat binary
+//	VUNAT_CONFIG        path of the config file
+//	VUNAT_PLUGIN_DIR    the ~/.vunat/plugins directory
+//	VUNAT_PROJECT       the project named by the first argument, if any
+//	VUNAT_PROJECT_JSON  that project's groups as JSON
+type PluginCommand struct {
+	Plugin     plugin.Plugin
+	ConfigPath string
+	Stdin      io.Reader
+	Stdout     io.Writer
+	Stderr     io.Writer
+}
+
+// NewPluginCommand constructs a PluginCommand for p wired to the terminal.
+func NewPluginCommand(p plugin.Plugin, configPath string) *PluginCommand {
+	return &PluginCommand{Plugin: p, ConfigPath: configPath, Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+}
+
+func (c *PluginCommand) Name() string { return c.Plugin.Name }
+func (c *PluginCommand) Help() string { return "Plugin: " + c.Plugin.Path }
+
+// RawArgs reports that plugins parse their own flags, including --help.
+func (c *PluginCommand) RawArgs() bool { return true }
+
+func (c *PluginCommand) Description() string {
+	return fmt.Sprintf("External command provided by %s.\n"+
+		"All arguments are passed to it unchanged; run 'vunat %s --help' for the\n"+
+		"plugin's own help.", c.Plugin.Path, c.Plugin.Name)
+}
+
+func (c *PluginCommand) Examples() []string { return nil }
+
+func (c *PluginCommand) Run(args []string) error {
+	cmd := exec.Command(c.Plugin.Path, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = c.Stdin, c.Stdout, c.Stderr
+	cmd.Env = append(os.Environ(), c.env(args)...)
+
+	// The plugin shares the terminal, so Ctrl+C reaches it directly; keep
+	// vunat alive until the plugin has decided how to exit.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("plugin %s: %w", c.Plugin.Name, exitErr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run plugin %s: %w", c.Plugin.Name, err)
+	}
+	return nil
+}
+
+// env returns the VUNAT_* variables describing the invocation.
+func (c *PluginCommand) env(args []string) []string {
+	env := []string{
+		"VUNAT_CONFIG=" + c.ConfigPath,
+		"VUNAT_PLUGIN_DIR=" + plugin.Dir(c.ConfigPath),
+	}
+	if exe, err := os.Executable(); err == nil {
+		env = append(env, "VUNAT_BIN="+exe)
+	}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if name, err := projects.Resolve(args[0]); err == nil {
+			env = append(env, "VUNAT_PROJECT="+name)
+			if proj, err := projects.Get(name); err == nil {
+				if data, err := json.Marshal(proj); err == nil {
+					env = append(env, "VUNAT_PROJECT_JSON="+string(data))
+				}
+			}
+		}
+	}
+	return env
+}
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/config"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+	"github.com/tanuvnair/vunat-cli/internal/suggest"
+)
+
+// ProjectAddCommand implements 'project add': it appends a command group to
+// a project, creating the project if needed. A group with the same name is
+// replaced in place.
+//
+// Usage: vunat project add <project_name> <group> <command>... [--path <dir>] [--env KEY=VALUE]... [--yes]
+type ProjectAddCommand struct {
+	cfg config.Manager
+	In  io.Reader
+	Out io.Writer
+}
+
+// NewProjectAddCommand constructs a ProjectAddCommand that writes through cfg.
+func NewProjectAddCommand(cfg config.Manager) *ProjectAddCommand {
+	return &ProjectAddCommand{cfg: cfg, In: os.Stdin, Out: os.Stdout}
+}
+
+func (c *ProjectAddCommand) Name() string { return "add" }
+func (c *ProjectAddCommand) Help() string { return "Add a command group to a project" }
+
+func (c *ProjectAddCommand) Description() string {
+	return "Adds a group of commands to a project, creating the project if it does not\n" +
+		"exist. Each command is a separate argument, so quote commands that contain\n" +
+		"spaces. The group runs in --path (default: the current directory)."
+}
+
+func (c *ProjectAddCommand) Examples() []string {
+	return []string{
+		`vunat project add gradepoint backend "go run ./cmd/api/main.go" "go run ./cmd/scheduler/main.go"`,
+		`vunat project add gradepoint frontend "npm run dev" --path ~/code/gradepoint/web --env PORT=3000 --env HOST=0.0.0.0`,
+	}
+}
+
+func (c *ProjectAddCommand) Spec() spec.Spec {
+	return spec.Spec{
+		Flags: []spec.Flag{
+			{Name: "path", Short: "p", Placeholder: "dir", Usage: "directory the commands run in (default: current directory)"},
+			{Name: "env", Short: "e", Placeholder: "KEY=VALUE", Repeated: true, Usage: "environment variable for the group; repeat for more"},
+			{Name: "yes", Short: "y", Kind: spec.Bool, Usage: "write without asking for confirmation"},
+		},
+		Args: []spec.Arg{
+			{Name: "project_name", Usage: "project to add the group to", Complete: completeProjects},
+			{Name: "group", Usage: "group name, used to prefix its output"},
+			{Name: "command", Variadic: true, Usage: "command to run in the group"},
+		},
+	}
+}
+
+func (c *ProjectAddCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *ProjectAddCommand) Exec(v *spec.Values) error {
+	dir := v.String("path")
+	if dir == "" {
+		dir = "."
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	group := projects.CommandGroup{Name: v.Arg(1), AbsolutePath: abs, Commands: projects.NewCommands(v.Args[2:]...)}
+	if env := v.Strings("env"); len(env) > 0 {
+		group.Env = make(map[string]string)
+		for _, pair := range env {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok || k == "" {
+				return &spec.UsageError{Msg: fmt.Sprintf("invalid --env entry %q: expected KEY=VALUE", pair)}
+			}
+			group.Env[k] = val
+		}
+	}
+
+	name := v.Arg(0)
+	_, err = updateConfig(c.cfg, v.Bool("yes"), newPrompter(c.In, c.Out), func(conf *projects.Config) (string, error) {
+		proj := conf.Projects[name]
+		for i, g := range proj.Groups {
+			if g.Name == group.Name {
+				fmt.Fprintf(c.Out, "Group %q already exists in %q and will be replaced.\n", group.Name, name)
+				proj.Groups[i] = group
+				return fmt.Sprintf("Saved group %q of project %q to %s", group.Name, name, c.cfg.Path()), nil
+			}
+		}
+		proj.Groups = append(proj.Groups, group)
+		conf.Projects[name] = proj
+		return fmt.Sprintf("Added group %q to project %q in %s", group.Name, name, c.cfg.Path()), nil
+	})
+	return err
+}
+
+// ProjectRemoveCommand implements 'project remove': it deletes a project, or
+// a single group of it, from the config file.
+//
+// Usage: vunat project remove <project_name> [group] [--yes]
+type ProjectRemoveCommand struct {
+	cfg config.Manager
+	In  io.Reader
+	Out io.Writer
+}
+
+// NewProjectRemoveCommand constructs a ProjectRemoveCommand that writes through cfg.
+func NewProjectRemoveCommand(cfg config.Manager) *ProjectRemoveCommand {
+	return &ProjectRemoveCommand{cfg: cfg, In: os.Stdin, Out: os.Stdout}
+}
+
+func (c *ProjectRemoveCommand) Name() string { return "remove" }
+func (c *ProjectRemoveCommand) Help() string { return "Remove a project or one of its groups" }
+
+func (c *ProjectRemoveCommand) Description() string {
+	return "Deletes a project from the config file, or only the named group when one is\n" +
+		"given. The change is shown as a diff and confirmed before it is written."
+}
+
+func (c *ProjectRemoveCommand) Examples() []string {
+	return []string{"vunat project remove gradepoint", "vunat project remove gradepoint frontend --yes"}
+}
+
+func (c *ProjectRemoveCommand) Spec() spec.Spec {
+	return spec.Spec{
+		Flags: []spec.Flag{
+			{Name: "yes", Short: "y", Kind: spec.Bool, Usage: "write without asking for confirmation"},
+		},
+		Args: []spec.Arg{
+			{Name: "project_name", Usage: "project to remove", Complete: completeProjects},
+			{Name: "group", Optional: true, Usage: "only remove this group", Complete: completeGroups},
+		},
+	}
+}
+
+func (c *ProjectRemoveCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *ProjectRemoveCommand) Exec(v *spec.Values) error {
+	name, group := v.Arg(0), v.Arg(1)
+	_, err := updateConfig(c.cfg, v.Bool("yes"), newPrompter(c.In, c.Out), func(conf *projects.Config) (string, error) {
+		proj, ok := conf.Projects[name]
+		if !ok {
+			return "", fmt.Errorf("unknown project %q%s", name, suggest.Hint(suggest.Similar(name, sortedProjectNames(conf.Projects))))
+		}
+		if group == "" {
+			delete(conf.Projects, name)
+			return fmt.Sprintf("Removed project %q from %s", name, c.cfg.Path()), nil
+		}
+		groups := make([]string, 0, len(proj.Groups))
+		for i, g := range proj.Groups {
+			if g.Name == group {
+				proj.Groups = append(proj.Groups[:i:i], proj.Groups[i+1:]...)
+				conf.Projects[name] = proj
+				return fmt.Sprintf("Removed group %q from project %q in %s", group, name, c.cfg.Path()), nil
+			}
+			groups = append(groups, g.Name)
+		}
+		return "", fmt.Errorf("project %q has no group %q%s", name, group, suggest.Hint(suggest.Similar(group, groups)))
+	})
+	return err
+}
+
+// completeGroups returns the group names of the project named by the first
+// positional argument, for shell completion.
+func completeGroups(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	proj, err := projects.Get(args[0])
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(proj.Groups))
+	for _, g := range proj.Groups {
+		names = append(names, g.Name)
+	}
+	return names
+}
+
+func sortedProjectNames(all map[string]projects.Project) []string {
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/config"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+func TestProjectAddEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]string
+		wantErr string
+	}{
+		{name: "none", args: nil},
+		{
+			name: "repeated",
+			args: []string{"--env", "PORT=3000", "-e", "ORIGINS=http://a,http://b", "--env=EMPTY="},
+			want: map[string]string{"PORT": "3000", "ORIGINS": "http://a,http://b", "EMPTY": ""},
+		},
+		{name: "last one wins", args: []string{"-e", "A=1", "-e", "A=2"}, want: map[string]string{"A": "2"}},
+		{name: "missing =", args: []string{"--env", "PORT"}, wantErr: `invalid --env entry "PORT"`},
+		{name: "missing key", args: []string{"--env", "=1"}, wantErr: `invalid --env entry "=1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewFSManager(filepath.Join(t.TempDir(), "config.json"))
+			c := &ProjectAddCommand{cfg: cfg, In: strings.NewReader(""), Out: io.Discard}
+			args := append([]string{"shop", "web", "npm start", "--yes"}, tt.args...)
+			v, err := spec.Parse("vunat project add", c.Spec(), args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.Exec(v)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Exec() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := cfg.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			conf, err := projects.Parse(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := conf.Projects["shop"].Groups[0].Env; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("env = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
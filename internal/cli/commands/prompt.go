@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// prompter asks questions on a terminal. It keeps a single buffered reader
+// over the input so consecutive prompts don't lose typed-ahead answers.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(in io.Reader, out io.Writer) *prompter {
+	return &prompter{in: bufio.NewReader(in), out: out}
+}
+
+// Confirm asks a yes/no question. An empty answer selects def; otherwise
+// only "y" or "yes" (case-insensitive) count as yes. At the end of the input
+// it returns an error wrapping io.EOF rather than guessing an answer.
+func (p *prompter) Confirm(question string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	fmt.Fprintf(p.out, "%s %s ", question, hint)
+	answer, err := p.readLine()
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// Ask prompts for a free-form answer, returning def when the answer is empty
+// and an error wrapping io.EOF at the end of the input.
+func (p *prompter) Ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	answer, err := p.readLine()
+	if err != nil {
+		return "", err
+	}
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// AskLines prompts for a list of answers, one per line, ended by an empty
+// line. def is listed first and kept when the list is left empty. Like Ask
+// it returns an error wrapping io.EOF if the input ends before the list does.
+func (p *prompter) AskLines(question string, def []string) ([]string, error) {
+	if len(def) > 0 {
+		fmt.Fprintf(p.out, "%s, one per line, then an empty line; none keeps:\n", question)
+		for _, d := range def {
+			fmt.Fprintf(p.out, "    %s\n", d)
+		}
+	} else {
+		fmt.Fprintf(p.out, "%s, one per line, then an empty line:\n", question)
+	}
+	var answers []string
+	for {
+		fmt.Fprint(p.out, "  > ")
+		answer, err := p.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			break
+		}
+		answers = append(answers, answer)
+	}
+	if len(answers) == 0 {
+		return def, nil
+	}
+	return answers, nil
+}
+
+// errNoAnswer reports that the input ended before a question was answered.
+// It wraps io.EOF.
+type errNoAnswer struct{}
+
+func (errNoAnswer) Error() string { return "no answer given: the input ended" }
+func (errNoAnswer) Unwrap() error { return io.EOF }
+
+// readLine reads one answer. A last line without a newline still counts;
+// io.EOF is returned only when the input ended with nothing read.
+func (p *prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	if err == io.EOF {
+		// Keep the prompt and any following output on separate lines.
+		fmt.Fprintln(p.out)
+		if line == "" {
+			return "", errNoAnswer{}
+		}
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+	"github.com/tanuvnair/vunat-cli/internal/runner"
+	"github.com/tanuvnair/vunat-cli/internal/suggest"
+)
+
+// RunCommand runs a one-off task defined in a project's "tasks" map.
+//
+// Usage: vunat run <project_name> <task> [-- args...]
+type RunCommand struct {
+	Runner *runner.Runner
+}
+
+// NewRunCommand constructs a RunCommand. If r is nil a default runner is created.
+func NewRunCommand(r *runner.Runner) *RunCommand {
+	if r == nil {
+		r = runner.New()
+	}
+	return &RunCommand{Runner: r}
+}
+
+func (c *RunCommand) Name() string { return "run" }
+func (c *RunCommand) Help() string { return "Run a one-off task of a project" }
+
+func (c *RunCommand) Description() string {
+	return "Runs a task such as a migration, seed or code generator in the directory and\n" +
+		"environment of its group, with its output streamed to the terminal. Arguments\n" +
+		"after -- are appended to the task's command. vunat exits with the task's\n" +
+		"exit status. `vunat list` shows the tasks of each project."
+}
+
+func (c *RunCommand) Examples() []string {
+	return []string{
+		"vunat run gradepoint migrate",
+		"vunat run gradepoint test -- -run TestLogin ./internal/auth",
+	}
+}
+
+func (c *RunCommand) Spec() spec.Spec {
+	return spec.Spec{Args: []spec.Arg{
+		{Name: "project_name", Usage: "registered project", Complete: completeProjects},
+		{Name: "task", Usage: "task to run", Complete: completeTasks},
+	}}
+}
+
+func (c *RunCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *RunCommand) Exec(v *spec.Values) error {
+	projectName, err := projects.Resolve(v.Arg(0))
+	if err != nil {
+		return err
+	}
+	proj, err := projects.Get(projectName)
+	if err != nil {
+		return err
+	}
+	name := v.Arg(1)
+	task, ok := proj.Tasks[name]
+	if !ok {
+		if len(proj.Tasks) == 0 {
+			return fmt.Errorf("project %q has no tasks; add a \"tasks\" map to it in the config file", projectName)
+		}
+		return fmt.Errorf("unknown task %q for project %q%s", name, projectName, suggest.Hint(suggest.Similar(name, proj.TaskNames())))
+	}
+
+	// The task shares the terminal, so Ctrl+C reaches it directly; let it
+	// decide how to exit and report its status.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	return c.Runner.RunTask(context.Background(), proj, task, v.Rest)
+}
+
+// completeTasks returns the task names of the project named by the first
+// positional argument, for shell completion.
+func completeTasks(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	proj, err := projects.Get(args[0])
+	if err != nil {
+		return nil
+	}
+	return proj.TaskNames()
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/tanuvnair/vunat-cli/internal/config"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// saveProject stores proj under name in the config file managed by cfg.
+// The resulting change is printed as a diff and, unless yes is set, the user
+// is asked to confirm before the file is written. It reports whether the
+// config file was updated.
+func saveProject(cfg config.Manager, name string, proj projects.Project, yes bool, p *prompter) (bool, error) {
+	return updateConfig(cfg, yes, p, func(conf *projects.Config) (string, error) {
+		if _, exists := conf.Projects[name]; exists {
+			fmt.Fprintf(p.out, "Project %q already exists and will be replaced.\n", name)
+		}
+		conf.Projects[name] = proj
+		return fmt.Sprintf("Saved project %q to %s", name, cfg.Path()), nil
+	})
+}
+
+// updateConfig loads the config file managed by cfg, applies change to it and
+// writes the result after showing a diff and, unless yes is set, asking for
+// confirmation. change returns the message printed once the file is written.
+// It reports whether the config file was updated.
+func updateConfig(cfg config.Manager, yes bool, p *prompter, change func(conf *projects.Config) (string, error)) (bool, error) {
+	out := p.out
+	if cfg == nil {
+		return false, fmt.Errorf("config manager not provided")
+	}
+	path, err := cfg.Ensure()
+	if err != nil {
+		return false, fmt.Errorf("failed to ensure config file: %w", err)
+	}
+	before, err := cfg.Read()
+	if err != nil {
+		return false, fmt.Errorf("failed to read config file: %w", err)
+	}
+	conf, err := projects.Parse(before)
+	if err != nil {
+		return false, err
+	}
+
+	done, err := change(&conf)
+	if err != nil {
+		return false, err
+	}
+	after, err := conf.Marshal()
+	if err != nil {
+		return false, err
+	}
+	if string(after) == string(before) {
+		fmt.Fprintf(out, "%s is already up to date.\n", path)
+		return false, nil
+	}
+
+	fmt.Fprintf(out, "Changes to %s:\n\n", path)
+	writeDiff(out, string(before), string(after))
+	fmt.Fprintln(out)
+
+	if !yes {
+		ok, err := p.Confirm("Write these changes?", false)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			fmt.Fprintln(out, "Aborted; config file left unchanged.")
+			return false, nil
+		}
+	}
+
+	if err := cfg.Write(after, 0o644); err != nil {
+		return false, fmt.Errorf("failed to write config file: %w", err)
+	}
+	fmt.Fprintln(out, done)
+	return true, nil
+}
@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tanuvnair/vunat-cli/internal/config"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+func TestSaveProjectKeepsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	before := `{"$schema": "https://example.com/vunat.json", "projects": {"old": []}, "editor": {"cmd": "vim"}}`
+	if err := os.WriteFile(path, []byte(before), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.NewFSManager(path)
+	proj := projects.Project{Groups: []projects.CommandGroup{{Name: "web", Commands: projects.NewCommands("npm start")}}}
+	saved, err := saveProject(cfg, "demo", proj, true, newPrompter(strings.NewReader(""), io.Discard))
+	if err != nil || !saved {
+		t.Fatalf("saveProject() = %v, %v; want true, nil", saved, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"$schema": "https://example.com/vunat.json"`, `"editor": {`, `"cmd": "vim"`, `"old": []`, `"demo": [`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config file lacks %s:\n%s", want, data)
+		}
+	}
+}
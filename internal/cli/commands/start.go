@@ -3,16 +3,25 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
+	"time"
 
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/console"
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
 	"github.com/tanuvnair/vunat-cli/internal/runner"
+	"github.com/tanuvnair/vunat-cli/internal/term"
+	"github.com/tanuvnair/vunat-cli/internal/tui"
 )
 
 // StartCommand starts a named project using the provided Runner.
 //
-// Usage: vunat start <project_name>
+// Usage: vunat start [--ui | --log-format json] [--attach group] <project_name>
 type StartCommand struct {
 	Runner *runner.Runner
 }
@@ -28,29 +37,251 @@ func NewStartCommand(r *runner.Runner) *StartCommand {
 func (c *StartCommand) Name() string { return "start" }
 func (c *StartCommand) Help() string { return "Start a project" }
 
-func (c *StartCommand) Run(args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: vunat start <project_name>")
+func (c *StartCommand) Description() string {
+	return "Starts the command groups of a project in order. Commands within a group run\n" +
+		"concurrently and their output is prefixed with the group name. The project\n" +
+		"stops when any process fails or when you press Ctrl+C, which asks every\n" +
+		"process to exit and kills it after 5 seconds; a second Ctrl+C kills at once.\n\n" +
+		"In an interactive terminal single keys control the running project: r <n>\n" +
+		"restarts process n, s shows the status, l <n> hides or shows the output of\n" +
+		"group n, c clears the screen, q stops gracefully and ? lists the keys.\n\n" +
+		"With --ui the project runs in a full-screen dashboard listing each process\n" +
+		"with its state, PID, uptime, CPU and memory use and restarts above a\n" +
+		"scrollable, searchable log. Select a process with the arrow keys, press r\n" +
+		"to restart it, x to stop it, / to search its log and q to quit.\n\n" +
+		"With --log-format json every output line is written to stdout as a JSON\n" +
+		"object with ts, project, group, command, pid, stream and line fields, and\n" +
+		"lifecycle changes (started, exited, restarting, ...) as records with an\n" +
+		"event field, for log tools that should not parse [group] prefixes.\n\n" +
+		"Input typed into vunat, or piped to it, goes to the command with \"stdin\": true\n" +
+		"or to the first service of the group named by --attach, for dev servers that\n" +
+		"take keys. In an interactive terminal Ctrl+] switches the keys between that\n" +
+		"process and vunat's controls, and i <n> sends them to process n instead."
+}
+
+func (c *StartCommand) Examples() []string {
+	return []string{"vunat start gradepoint", "vunat start --ui gradepoint", "vunat start --log-format json gradepoint | jq .", "vunat start --attach frontend gradepoint"}
+}
+
+func (c *StartCommand) Spec() spec.Spec {
+	return spec.Spec{
+		Args: []spec.Arg{{Name: "project_name", Usage: "registered project to start", Complete: completeProjects}},
+		Flags: []spec.Flag{
+			{Name: "ui", Kind: spec.Bool, Usage: "show a full-screen dashboard instead of the combined output"},
+			{Name: "log-format", Default: "text", Values: []string{"text", "json"}, Usage: "write output as prefixed text or as JSON lines"},
+			{Name: "attach", Placeholder: "group", Usage: "send vunat's input to the first service of group", Complete: completeGroups},
+		},
+	}
+}
+
+func (c *StartCommand) Run(args []string) error { return spec.Run(c, args) }
+
+func (c *StartCommand) Exec(v *spec.Values) error {
+	projectName, err := projects.Resolve(v.Arg(0))
+	if err != nil {
+		return err
 	}
-	projectName := args[0]
 
 	if c.Runner == nil {
 		c.Runner = runner.New()
 	}
+	ui, jsonLogs := v.Bool("ui"), v.String("log-format") == "json"
+	if ui && jsonLogs {
+		return &spec.UsageError{Msg: "--ui and --log-format json cannot be combined"}
+	}
+	if ui && !term.Interactive(os.Stdin, os.Stdout) {
+		return &spec.UsageError{Msg: "--ui needs an interactive terminal"}
+	}
+	attach := v.String("attach")
+	if attach != "" {
+		c.Runner.SetStdinTarget(attach)
+	}
+	input := attach != "" || readsStdin(projectName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// Create a context which is cancelled on SIGINT/SIGTERM (Ctrl+C).
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
+	var dash *tui.Dashboard
+	stop := func() {
+		fmt.Fprintln(os.Stderr, "Stopping all processes (Ctrl+C again to kill them)...")
+		if err := c.Runner.Stop(); err != nil {
+			cancel()
+		}
+	}
+	if ui {
+		dash = tui.New(c.Runner, projectName)
+		stop = dash.Quit
+	}
 
-	fmt.Printf("Starting project: %s\n\n", projectName)
+	// On SIGINT/SIGTERM (Ctrl+C) stop the processes gracefully, as q does,
+	// and kill them by cancelling the context on a second one. Remember the
+	// signal so the interruption is reported as such rather than as
+	// "context canceled".
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	var received atomic.Value
+	go func() {
+		select {
+		case sig := <-sigCh:
+			received.Store(sig)
+			go stop()
+		case <-ctx.Done():
+			return
+		}
+		select {
+		case <-sigCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// Size the pseudo-terminals of tty commands like this terminal and
+	// follow its size changes.
+	if cols, rows, err := term.Size(os.Stdout); err == nil {
+		c.Runner.SetTerminalSize(cols, rows)
+		resized := make(chan os.Signal, 1)
+		term.NotifyResize(resized)
+		defer signal.Stop(resized)
+		go func() {
+			for {
+				select {
+				case <-resized:
+					if cols, rows, err := term.Size(os.Stdout); err == nil {
+						c.Runner.SetTerminalSize(cols, rows)
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	if ui {
+		err = c.startUI(ctx, dash, projectName, input)
+		if sig, ok := received.Load().(os.Signal); ok {
+			return &exitcode.InterruptError{Signal: sig}
+		}
+		return err
+	}
+
+	var unsubscribe func()
+	controls := false
+	if jsonLogs {
+		// Nothing but the records goes to stdout.
+		unsubscribe = c.Runner.Subscribe(runner.NewJSONLines(os.Stdout))
+	} else {
+		fmt.Printf("Starting project: %s\n\n", projectName)
+		// The process output and the keyboard controls share stdout.
+		stdout := runner.NewSyncWriter(os.Stdout)
+		printer := runner.NewPrinter(stdout, runner.NewSyncWriter(os.Stderr))
+		unsubscribe = c.Runner.Subscribe(printer)
+
+		// Keyboard controls, only when run in the foreground of a terminal:
+		// piped and background sessions behave as before.
+		if term.Interactive(os.Stdin, os.Stdout) {
+			if restore, err := term.Cbreak(os.Stdin); err == nil {
+				defer restore()
+				ctrl := console.New(c.Runner, printer, stdout)
+				if input {
+					ctrl.FocusInput()
+				}
+				go ctrl.Run(ctx, os.Stdin)
+				controls = true
+			}
+		}
+	}
+	if input && !controls {
+		go c.forwardStdin(ctx, os.Stdin)
+	}
 
 	// Start the project by name. StartByName will load the project config and
 	// launch the processes. It blocks until processes exit or the context is cancelled.
-	if err := c.Runner.StartByName(ctx, projectName); err != nil {
+	err = c.Runner.StartByName(ctx, projectName)
+	if err != nil {
 		// Best-effort shutdown if StartByName returned an error.
 		_ = c.Runner.Shutdown()
+	}
+	unsubscribe()
+	if !jsonLogs {
+		// The exited records already tell how each process ended.
+		c.Runner.WriteSummary(os.Stderr)
+	}
+	if sig, ok := received.Load().(os.Signal); ok {
+		return &exitcode.InterruptError{Signal: sig}
+	}
+	return err
+}
+
+// startUI runs the project inside the full-screen dashboard and prints the
+// process summary once it is closed.
+func (c *StartCommand) startUI(ctx context.Context, dash *tui.Dashboard, projectName string, input bool) error {
+	restore, err := term.Cbreak(os.Stdin)
+	if err != nil {
 		return err
 	}
+	if input {
+		dash.FocusInput()
+	}
+	result := make(chan error, 1)
+	go func() {
+		err := c.Runner.StartByName(ctx, projectName)
+		if err != nil {
+			_ = c.Runner.Shutdown()
+		}
+		result <- err
+	}()
+	err = dash.Run(ctx, os.Stdin, os.Stdout, result)
+	_ = restore()
+	c.Runner.WriteSummary(os.Stderr)
+	return err
+}
 
-	return nil
+// readsStdin reports whether a command of the project named name sets
+// "stdin".
+func readsStdin(name string) bool {
+	proj, err := projects.Get(name)
+	if err != nil {
+		return false
+	}
+	for _, g := range proj.Groups {
+		for _, cmd := range g.Commands {
+			if cmd.Stdin {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// forwardStdin copies in to the process reading vunat's input, holding it
+// until that process has started, and closes its stdin at the end of in.
+func (c *StartCommand) forwardStdin(ctx context.Context, in io.Reader) {
+	target := func() int {
+		for {
+			if n := c.Runner.InputTarget(); n != 0 {
+				return n
+			}
+			select {
+			case <-ctx.Done():
+				return 0
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+	}
+	buf := make([]byte, 4096)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			if t := target(); t != 0 {
+				_ = c.Runner.WriteInput(t, buf[:n])
+			}
+		}
+		if err != nil {
+			if t := target(); t != 0 {
+				_ = c.Runner.CloseInput(t)
+			}
+			return
+		}
+	}
 }
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// FileCompletion is emitted by Complete when the shell should complete file
+// names itself.
+const FileCompletion = ":files"
+
+// Complete returns shell completion candidates for words, the arguments
+// typed after the program name; the last word is the one being completed and
+// may be empty. Each candidate is a value optionally followed by a tab and a
+// description. Candidates are filtered by the prefix being completed.
+func (r *Registry) Complete(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	prev := words[:len(words)-1]
+
+	// A global flag expecting a value may precede the current word anywhere.
+	if len(prev) > 0 {
+		if g := r.lookupGlobal(prev[len(prev)-1]); g != nil && g.flag.Kind != spec.Bool && !strings.Contains(prev[len(prev)-1], "=") {
+			return filter(flagValues(g.flag, nil), cur)
+		}
+	}
+
+	// Locate the command name, skipping global flags and their values.
+	cmdIdx := -1
+	for i := 0; i < len(prev); i++ {
+		if g := r.lookupGlobal(prev[i]); g != nil {
+			if g.flag.Kind != spec.Bool && !strings.Contains(prev[i], "=") {
+				i++
+			}
+			continue
+		}
+		cmdIdx = i
+		break
+	}
+
+	if cmdIdx < 0 {
+		if strings.HasPrefix(cur, "-") {
+			return filter(flagNames(r.GlobalFlags()), cur)
+		}
+		var out []string
+		for _, c := range r.Commands() {
+			out = append(out, c.Name()+"\t"+c.Help())
+		}
+		for _, a := range r.Aliases() {
+			out = append(out, a+"\talias for "+r.aliases[a])
+		}
+		for _, c := range r.External() {
+			out = append(out, c.Name()+"\t"+c.Help())
+		}
+		return filter(out, cur)
+	}
+
+	if cmd, ok := r.commands[prev[cmdIdx]]; ok {
+		return r.completeCommand(cmd, prev[cmdIdx+1:], cur, r)
+	}
+
+	// Complete after an alias as if its expansion had been typed.
+	fields, err := projects.SplitArgs(r.aliases[prev[cmdIdx]])
+	if err != nil || len(fields) == 0 {
+		return nil
+	}
+	if _, isCmd := r.commands[fields[0]]; !isCmd {
+		return nil
+	}
+	expanded := append(append(append([]string{}, prev[:cmdIdx]...), fields...), words[cmdIdx+1:]...)
+	return r.Complete(expanded)
+}
+
+// completeCommand completes cur for cmd, given the words typed between the
+// command name and cur. Groups descend into their subcommands; top is the
+// root registry, which owns the global flags.
+func (r *Registry) completeCommand(cmd Command, rest []string, cur string, top *Registry) []string {
+	if g, ok := cmd.(*Group); ok {
+		for i := 0; i < len(rest); i++ {
+			if gf := top.lookupGlobal(rest[i]); gf != nil {
+				if gf.flag.Kind != spec.Bool && !strings.Contains(rest[i], "=") {
+					i++
+				}
+				continue
+			}
+			if sub, ok := g.sub.commands[rest[i]]; ok {
+				return g.sub.completeCommand(sub, rest[i+1:], cur, top)
+			}
+			return nil
+		}
+		if strings.HasPrefix(cur, "-") {
+			return filter(flagNames(top.GlobalFlags()), cur)
+		}
+		var out []string
+		for _, c := range g.sub.Commands() {
+			out = append(out, c.Name()+"\t"+c.Help())
+		}
+		return filter(out, cur)
+	}
+
+	sc, ok := cmd.(Specifier)
+	if !ok {
+		return nil
+	}
+	s := sc.Spec()
+
+	// Walk the words after the command to find positionals and whether the
+	// current word is the value of a flag. Global flags end at the first
+	// positional, as in Registry.Run.
+	var positional []string
+	for i := 0; i < len(rest); i++ {
+		w := rest[i]
+		if w == "--" {
+			return nil
+		}
+		if g := top.lookupGlobal(w); g != nil && len(positional) == 0 {
+			if g.flag.Kind != spec.Bool && !strings.Contains(w, "=") {
+				i++
+			}
+			continue
+		}
+		if len(w) > 1 && w[0] == '-' {
+			f := lookupFlag(s, w)
+			if f != nil && f.Kind != spec.Bool && !strings.Contains(w, "=") {
+				if i == len(rest)-1 {
+					return filter(flagValues(*f, positional), cur)
+				}
+				i++
+			}
+			continue
+		}
+		positional = append(positional, w)
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		flags := s.Flags
+		if len(positional) == 0 {
+			flags = append(flags[:len(flags):len(flags)], top.GlobalFlags()...)
+		}
+		return filter(flagNames(flags), cur)
+	}
+	arg := argAt(s, len(positional))
+	switch {
+	case arg == nil:
+		return nil
+	case arg.Complete != nil:
+		return filter(arg.Complete(positional), cur)
+	case arg.Path:
+		return []string{FileCompletion}
+	}
+	return nil
+}
+
+// argAt returns the spec argument at position i, accounting for a trailing
+// variadic argument.
+func argAt(s spec.Spec, i int) *spec.Arg {
+	if i < len(s.Args) {
+		return &s.Args[i]
+	}
+	if n := len(s.Args); n > 0 && s.Args[n-1].Variadic {
+		return &s.Args[n-1]
+	}
+	return nil
+}
+
+func lookupFlag(s spec.Spec, word string) *spec.Flag {
+	name, _, _ := strings.Cut(word, "=")
+	for i := range s.Flags {
+		f := &s.Flags[i]
+		if name == "--"+f.Name || (f.Short != "" && name == "-"+f.Short) {
+			return f
+		}
+	}
+	return nil
+}
+
+func flagNames(flags []spec.Flag) []string {
+	out := make([]string, 0, len(flags))
+	for _, f := range flags {
+		if !f.Hidden {
+			out = append(out, "--"+f.Name+"\t"+f.Usage)
+		}
+	}
+	return out
+}
+
+func flagValues(f spec.Flag, positional []string) []string {
+	if f.Complete != nil {
+		return f.Complete(positional)
+	}
+	return f.Values
+}
+
+// filter keeps the candidates whose value starts with prefix.
+func filter(candidates []string, prefix string) []string {
+	out := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		value, _, _ := strings.Cut(c, "\t")
+		if strings.HasPrefix(value, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// Group is a command that holds nested subcommands, e.g. `vunat config edit`.
+// Subcommands are resolved like top-level commands (exact name, unique
+// prefix, "did you mean" suggestions) and get their own --help. Groups can
+// be nested and are registered like any other command.
+type Group struct {
+	name string
+	help string
+	// Default runs when the group is invoked without a subcommand. If nil,
+	// the group's usage is printed instead.
+	Default Command
+	sub     *Registry
+}
+
+// NewGroup constructs a Group with the given subcommands.
+func NewGroup(name, help string, cmds ...Command) *Group {
+	g := &Group{name: name, help: help, sub: NewRegistry()}
+	g.setPath("vunat " + name)
+	for _, c := range cmds {
+		g.Register(c)
+	}
+	return g
+}
+
+// Register adds or replaces a subcommand.
+func (g *Group) Register(c Command) {
+	g.sub.Register(c)
+}
+
+func (g *Group) Name() string { return g.name }
+func (g *Group) Help() string { return g.help }
+
+// Run dispatches to the subcommand named by args[0].
+func (g *Group) Run(args []string) error {
+	if len(args) == 0 || (g.Default != nil && strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help") {
+		if g.Default == nil {
+			fmt.Print(g.usage())
+			return nil
+		}
+		return g.sub.dispatch(g.Default, args)
+	}
+	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Print(g.usage())
+		return nil
+	}
+	cmd, rest, err := g.sub.resolve(args[0], args[1:])
+	if err != nil {
+		return err
+	}
+	return g.sub.dispatch(cmd, rest)
+}
+
+// Renamed returns cmd mounted under another name, keeping its spec,
+// description and examples; e.g. the `config` command becomes
+// `vunat config edit` inside the config group.
+func Renamed(name string, cmd Specifier) Command {
+	return renamed{Specifier: cmd, name: name}
+}
+
+type renamed struct {
+	Specifier
+	name string
+}
+
+func (c renamed) Name() string { return c.name }
+
+func (c renamed) Description() string {
+	if d, ok := c.Specifier.(Describer); ok {
+		return d.Description()
+	}
+	return ""
+}
+
+func (c renamed) Examples() []string {
+	if d, ok := c.Specifier.(Describer); ok {
+		return d.Examples()
+	}
+	return nil
+}
+
+// setPath records where the group is mounted so usage strings and nested
+// groups show the full command line.
+func (g *Group) setPath(path string) {
+	g.sub.path = path
+	for _, c := range g.sub.commands {
+		if child, ok := c.(*Group); ok {
+			child.setPath(path + " " + child.Name())
+		}
+	}
+}
+
+// usage renders the group's help: its subcommands and their summaries.
+func (g *Group) usage() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s - %s\n\n", g.sub.path, g.help)
+	fmt.Fprintf(&b, "usage: %s <command> [args]\n", g.sub.path)
+	if g.Default != nil {
+		fmt.Fprintf(&b, "       %s            (same as '%s %s')\n", g.sub.path, g.sub.path, g.Default.Name())
+	}
+	b.WriteString("\nCommands:\n")
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, c := range g.sub.Commands() {
+		fmt.Fprintf(w, "  %s\t%s\n", c.Name(), c.Help())
+	}
+	_ = w.Flush()
+	fmt.Fprintf(&b, "\nRun '%s <command> --help' for details about a command.\n", g.sub.path)
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
+)
+
+// Describer is optionally implemented by commands that provide detailed help
+// for `vunat help <command>` and `vunat <command> --help`. Commands that don't
+// implement it are described by their one-line Help and, for Specifier
+// commands, the generated usage.
+type Describer interface {
+	Command
+	// Description returns one or more paragraphs explaining the command.
+	Description() string
+	// Examples returns example invocations, each optionally followed by a
+	// "  # comment" explaining it.
+	Examples() []string
+}
+
+// HelpText returns the detailed help for the command at path, e.g.
+// ["start"] or ["config", "edit"]. An empty path returns nothing.
+func (r *Registry) HelpText(path []string) (string, error) {
+	if len(path) == 0 {
+		return "", nil
+	}
+	cmd, _, err := r.resolve(path[0], nil)
+	if err != nil {
+		return "", err
+	}
+	if g, ok := cmd.(*Group); ok && len(path) > 1 {
+		return g.sub.HelpText(path[1:])
+	}
+	return commandHelp(cmd, r.path+" "+cmd.Name()), nil
+}
+
+// SubcommandNames returns the visible command names at path: the top-level
+// commands for an empty path, or the subcommands of the group it names.
+func (r *Registry) SubcommandNames(path []string) []string {
+	if len(path) == 0 {
+		return r.visibleNames()
+	}
+	if g, ok := r.commands[path[0]].(*Group); ok {
+		return g.sub.SubcommandNames(path[1:])
+	}
+	return nil
+}
+
+// commandHelp renders the detailed help for cmd invoked as path.
+func commandHelp(cmd Command, path string) string {
+	if g, ok := cmd.(*Group); ok {
+		return g.usage()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s - %s\n", path, cmd.Help())
+
+	d, hasDetail := cmd.(Describer)
+	if hasDetail && d.Description() != "" {
+		b.WriteString("\n" + strings.TrimRight(d.Description(), "\n") + "\n")
+	}
+
+	b.WriteString("\n")
+	if sc, ok := cmd.(Specifier); ok {
+		b.WriteString(spec.Usage(path, sc.Spec()))
+	} else {
+		b.WriteString("usage: " + path + " [args]\n")
+	}
+
+	if hasDetail && len(d.Examples()) > 0 {
+		b.WriteString("\nExamples:\n")
+		for _, ex := range d.Examples() {
+			b.WriteString("  " + ex + "\n")
+		}
+	}
+	return b.String()
+}
@@ -2,12 +2,18 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/tanuvnair/vunat-cli/internal/cli/commands"
+	"github.com/tanuvnair/vunat-cli/internal/cli/spec"
 	"github.com/tanuvnair/vunat-cli/internal/config"
+	"github.com/tanuvnair/vunat-cli/internal/doctor"
 	"github.com/tanuvnair/vunat-cli/internal/launcher"
+	"github.com/tanuvnair/vunat-cli/internal/output"
+	"github.com/tanuvnair/vunat-cli/internal/plugin"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
 	"github.com/tanuvnair/vunat-cli/internal/runner"
 )
 
@@ -18,14 +24,66 @@ func Run(args []string) error {
 	cfgMgr := config.NewFSManager("")
 	osLauncher := launcher.NewOSLauncher(false)
 	runr := runner.New()
+	printer := output.NewPrinter(os.Stdout)
 
 	// Build registry and register commands
 	reg := NewRegistry()
 
-	// Register command implementations (start, list, config)
+	// Global options may appear before or after the command name, but not
+	// among the command's arguments.
+	formats := make([]string, 0, len(output.Formats))
+	for _, f := range output.Formats {
+		formats = append(formats, string(f))
+	}
+	reg.AddGlobal(spec.Flag{Name: "output", Short: "o", Values: formats, Usage: "print results in a machine-readable format"},
+		func(value string) error {
+			f, err := output.ParseFormat(value)
+			printer.Format = f
+			return err
+		})
+
+	// User-defined aliases from the config file.
+	reg.SetAliases(projects.Aliases())
+
+	// Unknown commands fall back to vunat-<name> plugins.
+	pluginDirs := plugin.SearchPath(plugin.Dir(cfgMgr.Path()))
+	reg.SetExternal(
+		func(name string) Command {
+			if p, ok := plugin.Find(name, pluginDirs); ok {
+				return commands.NewPluginCommand(p, cfgMgr.Path())
+			}
+			return nil
+		},
+		func() []Command {
+			var cmds []Command
+			for _, p := range plugin.Discover(pluginDirs) {
+				cmds = append(cmds, commands.NewPluginCommand(p, cfgMgr.Path()))
+			}
+			return cmds
+		},
+	)
+
+	// Register command implementations
 	reg.Register(commands.NewStartCommand(runr))
-	reg.Register(commands.NewListCommand())
-	reg.Register(commands.NewConfigCommand(cfgMgr, osLauncher))
+	reg.Register(commands.NewRunCommand(runr))
+	reg.Register(commands.NewListCommand(printer))
+	configEdit := Renamed("edit", commands.NewConfigCommand(cfgMgr, osLauncher))
+	configGroup := NewGroup("config", "Open or locate the config file",
+		configEdit,
+		commands.NewConfigPathCommand(cfgMgr),
+	)
+	configGroup.Default = configEdit
+	reg.Register(configGroup)
+	reg.Register(NewGroup("project", "Add or remove projects and groups",
+		commands.NewProjectAddCommand(cfgMgr),
+		commands.NewProjectRemoveCommand(cfgMgr),
+	))
+	reg.Register(commands.NewInitCommand(cfgMgr))
+	reg.Register(commands.NewImportCommand(cfgMgr))
+	reg.Register(commands.NewExportCommand())
+	reg.Register(commands.NewDoctorCommand(doctor.New(cfgMgr), printer))
+	reg.Register(commands.NewCompletionCommand())
+	reg.Register(commands.NewCompleteCommand(reg.Complete))
 
 	// Register dynamic help command. The provider builds help text from the
 	// registry contents so help is always up-to-date.
@@ -33,7 +91,7 @@ func Run(args []string) error {
 		var b strings.Builder
 		b.WriteString("vunat-cli - your personal CLI for quick-starting development projects\n\n")
 		b.WriteString("usage:\n")
-		b.WriteString("  vunat <command> [args]\n\n")
+		b.WriteString("  vunat [global options] <command> [args]\n\n")
 		b.WriteString("Available commands:\n")
 
 		// Use tabwriter to align command names and their descriptions in columns.
@@ -43,10 +101,32 @@ func Run(args []string) error {
 		}
 		_ = w.Flush()
 
-		b.WriteString("\n")
+		if aliases := reg.Aliases(); len(aliases) > 0 {
+			b.WriteString("\nAliases:\n")
+			w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+			for _, a := range aliases {
+				fmt.Fprintf(w, "  %s\t%s\n", a, reg.AliasTarget(a))
+			}
+			_ = w.Flush()
+		}
+
+		if plugins := reg.External(); len(plugins) > 0 {
+			b.WriteString("\nPlugins:\n")
+			w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+			for _, p := range plugins {
+				fmt.Fprintf(w, "  %s\t%s\n", p.Name(), p.Help())
+			}
+			_ = w.Flush()
+		}
+
+		b.WriteString("\nGlobal options:\n")
+		spec.WriteFlags(&b, reg.GlobalFlags())
+		b.WriteString("\nRun 'vunat help <command>' for details about a command.\n")
 		return b.String()
 	}
-	reg.Register(commands.NewHelpCommand(helpProvider))
+	help := commands.NewHelpCommand(helpProvider, reg.HelpText)
+	help.Names = reg.SubcommandNames
+	reg.Register(help)
 
 	// Dispatch to the registry
 	return reg.Run(args)
@@ -0,0 +1,370 @@
+// Package spec provides declarative flag and argument parsing for CLI
+// commands. A command describes what it accepts with a Spec; Parse turns raw
+// arguments into Values and reports problems as *UsageError so every command
+// fails the same way, and Usage renders the synopsis shown in errors and help.
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
+)
+
+// Kind is the type of value a flag takes.
+type Kind int
+
+const (
+	// String flags take a value: --name value or --name=value.
+	String Kind = iota
+	// Bool flags take no value: --yes (or explicitly --yes=false).
+	Bool
+	// Int flags take an integer value.
+	Int
+)
+
+// Flag declares a command-line option.
+type Flag struct {
+	// Name is the long name used as --name.
+	Name string
+	// Short is an optional single-letter alias used as -s.
+	Short string
+	Kind  Kind
+	// Default is returned by Values when the flag is not given.
+	Default string
+	// Values optionally restricts the accepted values.
+	Values []string
+	// Repeated flags may be given more than once; Values.Strings returns
+	// all of their values.
+	Repeated bool
+	// Placeholder names the value in usage output; defaults to the kind.
+	Placeholder string
+	Usage       string
+	// Hidden flags are accepted but left out of usage output.
+	Hidden bool
+	// Complete optionally returns dynamic candidates for shell completion.
+	Complete Completer
+}
+
+// Arg declares a positional argument.
+type Arg struct {
+	Name     string
+	Optional bool
+	// Variadic accepts any number (zero or more if Optional, else one or more)
+	// of values; it must be the last argument.
+	Variadic bool
+	Usage    string
+	// Complete optionally returns candidates for shell completion.
+	Complete Completer
+	// Path marks arguments that name files or directories; shells fall back
+	// to filename completion for them.
+	Path bool
+}
+
+// Completer returns shell completion candidates given the positional
+// arguments that precede the word being completed. Candidates may carry a
+// description after a tab character.
+type Completer func(args []string) []string
+
+// Spec declares the flags and positional arguments a command accepts.
+type Spec struct {
+	Flags []Flag
+	Args  []Arg
+}
+
+// ErrHelp is returned by Parse when -h or --help is given.
+var ErrHelp = errors.New("help requested")
+
+// UsageError reports invalid command-line usage. The CLI exits with status
+// exitcode.Usage (2) for these errors.
+type UsageError struct {
+	// Msg describes the problem, e.g. "unknown flag --frmat".
+	Msg string
+	// Usage is the synopsis line printed after the message; may be empty.
+	Usage string
+}
+
+// ExitCode returns exitcode.Usage.
+func (e *UsageError) ExitCode() int { return exitcode.Usage }
+
+func (e *UsageError) Error() string {
+	if e.Usage == "" {
+		return e.Msg
+	}
+	return e.Msg + "\n\n" + strings.TrimRight(e.Usage, "\n")
+}
+
+// Values holds the result of parsing arguments against a Spec.
+type Values struct {
+	flags map[string]string
+	set   map[string]bool
+	lists map[string][]string
+	// Args are the positional arguments before any "--".
+	Args []string
+	// Rest are the arguments after a literal "--", passed through untouched.
+	Rest []string
+}
+
+// String returns the value of a flag, or its default if it was not given.
+// For a repeated flag it is the last value given.
+func (v *Values) String(name string) string { return v.flags[name] }
+
+// Strings returns every value given for a repeated flag, in order.
+func (v *Values) Strings(name string) []string { return v.lists[name] }
+
+// Bool reports whether a boolean flag is set to true.
+func (v *Values) Bool(name string) bool {
+	b, _ := strconv.ParseBool(v.flags[name])
+	return b
+}
+
+// Int returns the value of an integer flag; Parse has already validated it.
+func (v *Values) Int(name string) int {
+	n, _ := strconv.Atoi(v.flags[name])
+	return n
+}
+
+// IsSet reports whether the flag was given explicitly.
+func (v *Values) IsSet(name string) bool { return v.set[name] }
+
+// Arg returns the i-th positional argument, or "" if absent.
+func (v *Values) Arg(i int) string {
+	if i < len(v.Args) {
+		return v.Args[i]
+	}
+	return ""
+}
+
+// Parse parses args against s. Flags and positional arguments may be mixed;
+// everything after "--" is collected in Values.Rest. cmd is the command path
+// (e.g. "vunat export") used to build the usage text of errors.
+func Parse(cmd string, s Spec, args []string) (*Values, error) {
+	v := &Values{flags: make(map[string]string), set: make(map[string]bool), lists: make(map[string][]string)}
+	for _, f := range s.Flags {
+		if f.Default != "" {
+			v.flags[f.Name] = f.Default
+		}
+	}
+	fail := func(format string, a ...any) (*Values, error) {
+		return nil, &UsageError{Msg: fmt.Sprintf(format, a...), Usage: "usage: " + Synopsis(cmd, s)}
+	}
+
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			v.Rest = append(v.Rest, args[i+1:]...)
+			break
+		}
+		if len(a) < 2 || a[0] != '-' {
+			v.Args = append(v.Args, a)
+			continue
+		}
+		if a == "-h" || a == "--help" {
+			return nil, ErrHelp
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		f := s.lookup(name, !strings.HasPrefix(a, "--"))
+		if f == nil {
+			return fail("unknown flag %s", strings.SplitN(a, "=", 2)[0])
+		}
+		if f.Kind == Bool {
+			if !hasValue {
+				value = "true"
+			}
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fail("invalid value %q for --%s: expected true or false", value, f.Name)
+			}
+		} else if !hasValue {
+			if i+1 >= len(args) {
+				return fail("flag --%s requires a value", f.Name)
+			}
+			i++
+			value = args[i]
+		}
+		if f.Kind == Int {
+			if _, err := strconv.Atoi(value); err != nil {
+				return fail("invalid value %q for --%s: expected an integer", value, f.Name)
+			}
+		}
+		if len(f.Values) > 0 && !contains(f.Values, value) {
+			return fail("invalid value %q for --%s (expected one of: %s)", value, f.Name, strings.Join(f.Values, ", "))
+		}
+		v.flags[f.Name] = value
+		v.set[f.Name] = true
+		if f.Repeated {
+			v.lists[f.Name] = append(v.lists[f.Name], value)
+		}
+	}
+
+	min, max := s.argRange()
+	switch {
+	case len(v.Args) < min:
+		return fail("missing %s", s.Args[len(v.Args)].placeholder())
+	case max >= 0 && len(v.Args) > max:
+		return fail("unexpected argument %q", v.Args[max])
+	}
+	return v, nil
+}
+
+// Usage renders the synopsis and option list for cmd, e.g.
+//
+//	usage: vunat export [options] <project_name>
+func Usage(cmd string, s Spec) string {
+	var b strings.Builder
+	b.WriteString("usage: " + Synopsis(cmd, s) + "\n")
+
+	visible := s.visibleFlags()
+	if len(s.Args) > 0 && hasArgUsage(s.Args) {
+		b.WriteString("\nArguments:\n")
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		for _, a := range s.Args {
+			fmt.Fprintf(w, "  %s\t%s\n", a.placeholder(), a.Usage)
+		}
+		_ = w.Flush()
+	}
+	if len(visible) > 0 {
+		b.WriteString("\nOptions:\n")
+		WriteFlags(&b, visible)
+	}
+	return b.String()
+}
+
+// Synopsis returns the one-line form of Usage without the "usage: " prefix.
+func Synopsis(cmd string, s Spec) string {
+	parts := []string{cmd}
+	if len(s.visibleFlags()) > 0 {
+		parts = append(parts, "[options]")
+	}
+	for _, a := range s.Args {
+		parts = append(parts, a.synopsis())
+	}
+	return strings.Join(parts, " ")
+}
+
+// WriteFlags writes an aligned option list, one flag per line.
+func WriteFlags(b *strings.Builder, flags []Flag) {
+	w := tabwriter.NewWriter(b, 0, 0, 2, ' ', 0)
+	for _, f := range flags {
+		names := "    --" + f.Name
+		if f.Short != "" {
+			names = "-" + f.Short + ", --" + f.Name
+		}
+		if f.Kind != Bool {
+			names += " " + f.placeholder()
+		}
+		usage := f.Usage
+		if f.Default != "" && f.Kind != Bool {
+			usage += fmt.Sprintf(" (default %q)", f.Default)
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", names, usage)
+	}
+	_ = w.Flush()
+}
+
+func (s Spec) lookup(name string, short bool) *Flag {
+	for i := range s.Flags {
+		f := &s.Flags[i]
+		if (short && f.Short == name) || (!short && f.Name == name) {
+			return f
+		}
+	}
+	return nil
+}
+
+func (s Spec) visibleFlags() []Flag {
+	out := make([]Flag, 0, len(s.Flags))
+	for _, f := range s.Flags {
+		if !f.Hidden {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// argRange returns the minimum and maximum number of positional arguments;
+// max is -1 when the last argument is variadic.
+func (s Spec) argRange() (min, max int) {
+	for _, a := range s.Args {
+		if !a.Optional {
+			min++
+		}
+		if a.Variadic {
+			return min, -1
+		}
+		max++
+	}
+	return min, max
+}
+
+func (a Arg) placeholder() string {
+	return "<" + a.Name + ">"
+}
+
+func (a Arg) synopsis() string {
+	s := a.placeholder()
+	if a.Variadic {
+		s += "..."
+	}
+	if a.Optional {
+		s = "[" + s + "]"
+	}
+	return s
+}
+
+func (f Flag) placeholder() string {
+	switch {
+	case f.Placeholder != "":
+		return f.Placeholder
+	case len(f.Values) > 0:
+		return strings.Join(f.Values, "|")
+	case f.Kind == Int:
+		return "int"
+	}
+	return "string"
+}
+
+func hasArgUsage(args []Arg) bool {
+	for _, a := range args {
+		if a.Usage != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Runnable is implemented by commands driven by a Spec: Exec receives the
+// parsed Values instead of raw arguments.
+type Runnable interface {
+	Name() string
+	Spec() Spec
+	Exec(v *Values) error
+}
+
+// Run parses args against c's Spec and executes it. Commands use it to
+// implement the plain Run(args) method for callers outside the registry;
+// -h/--help prints the usage text to stdout.
+func Run(c Runnable, args []string) error {
+	cmd := "vunat " + c.Name()
+	v, err := Parse(cmd, c.Spec(), args)
+	if errors.Is(err, ErrHelp) {
+		fmt.Print(Usage(cmd, c.Spec()))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return c.Exec(v)
+}
@@ -0,0 +1,140 @@
+package spec
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	s := Spec{
+		Flags: []Flag{
+			{Name: "format", Short: "f", Default: "sh", Values: []string{"sh", "compose"}},
+			{Name: "yes", Short: "y", Kind: Bool},
+			{Name: "count", Kind: Int},
+		},
+		Args: []Arg{
+			{Name: "project"},
+			{Name: "group", Optional: true, Variadic: true},
+		},
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		flags   map[string]string
+		set     []string
+		pos     []string
+		rest    []string
+		wantErr string
+	}{
+		{name: "defaults", args: []string{"shop"}, flags: map[string]string{"format": "sh"}, pos: []string{"shop"}},
+		{
+			name:  "flags mixed with arguments",
+			args:  []string{"-y", "shop", "--format=compose", "web", "--count", "3", "api"},
+			flags: map[string]string{"format": "compose", "yes": "true", "count": "3"},
+			set:   []string{"count", "format", "yes"},
+			pos:   []string{"shop", "web", "api"},
+		},
+		{name: "short flag with value", args: []string{"-f", "compose", "shop"}, flags: map[string]string{"format": "compose"}, set: []string{"format"}, pos: []string{"shop"}},
+		{name: "explicit bool", args: []string{"--yes=false", "shop"}, flags: map[string]string{"format": "sh", "yes": "false"}, set: []string{"yes"}, pos: []string{"shop"}},
+		{
+			name:  "rest after --",
+			args:  []string{"shop", "--", "-y", "--format", "x"},
+			flags: map[string]string{"format": "sh"},
+			pos:   []string{"shop"},
+			rest:  []string{"-y", "--format", "x"},
+		},
+		{name: "dash is an argument", args: []string{"-"}, flags: map[string]string{"format": "sh"}, pos: []string{"-"}},
+		{name: "missing argument", args: []string{"-y"}, wantErr: "missing <project>"},
+		{name: "unknown flag", args: []string{"shop", "--frmat=sh"}, wantErr: "unknown flag --frmat"},
+		{name: "long name used as short", args: []string{"-format", "sh", "shop"}, wantErr: "unknown flag -format"},
+		{name: "missing value", args: []string{"shop", "--format"}, wantErr: "requires a value"},
+		{name: "value not allowed", args: []string{"shop", "-f", "zip"}, wantErr: `invalid value "zip" for --format`},
+		{name: "bad bool", args: []string{"shop", "--yes=maybe"}, wantErr: "expected true or false"},
+		{name: "bad int", args: []string{"shop", "--count", "x"}, wantErr: "expected an integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Parse("vunat test", s, tt.args)
+			if tt.wantErr != "" {
+				var ue *UsageError
+				if !errors.As(err, &ue) || !strings.Contains(ue.Msg, tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want a usage error containing %q", err, tt.wantErr)
+				}
+				if ue.Usage != "usage: vunat test [options] <project> [<group>...]" {
+					t.Errorf("usage = %q", ue.Usage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(v.flags, tt.flags) {
+				t.Errorf("flags = %v, want %v", v.flags, tt.flags)
+			}
+			for _, f := range s.Flags {
+				if want := contains(tt.set, f.Name); v.IsSet(f.Name) != want {
+					t.Errorf("IsSet(%q) = %v, want %v", f.Name, v.IsSet(f.Name), want)
+				}
+			}
+			if !reflect.DeepEqual(v.Args, tt.pos) || !reflect.DeepEqual(v.Rest, tt.rest) {
+				t.Errorf("args = %q, rest = %q; want %q, %q", v.Args, v.Rest, tt.pos, tt.rest)
+			}
+		})
+	}
+}
+
+func TestParseRepeated(t *testing.T) {
+	s := Spec{Flags: []Flag{{Name: "env", Short: "e", Repeated: true}, {Name: "path"}}}
+	v, err := Parse("vunat test", s, []string{"-e", "A=1,2", "--env=B=", "--path", "x", "-e", "A=3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := v.Strings("env"), []string{"A=1,2", "B=", "A=3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Strings(env) = %q, want %q", got, want)
+	}
+	if got := v.String("env"); got != "A=3" {
+		t.Errorf("String(env) = %q, want the last value", got)
+	}
+	if got := v.Strings("path"); got != nil {
+		t.Errorf("Strings(path) = %q, want nil for a flag that is not repeated", got)
+	}
+}
+
+func TestParseHelp(t *testing.T) {
+	for _, args := range [][]string{{"-h"}, {"shop", "--help"}} {
+		if _, err := Parse("vunat test", Spec{Args: []Arg{{Name: "project"}}}, args); !errors.Is(err, ErrHelp) {
+			t.Errorf("Parse(%q) = %v, want ErrHelp", args, err)
+		}
+	}
+	if _, err := Parse("vunat test", Spec{}, []string{"--", "-h"}); err != nil {
+		t.Errorf("Parse(-- -h) = %v, want no error", err)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	s := Spec{
+		Flags: []Flag{
+			{Name: "format", Short: "f", Default: "sh", Usage: "output format"},
+			{Name: "yes", Short: "y", Kind: Bool, Default: "false", Usage: "skip the prompt"},
+			{Name: "debug", Kind: Bool, Hidden: true},
+		},
+		Args: []Arg{{Name: "project", Usage: "registered project"}},
+	}
+	want := `usage: vunat export [options] <project>
+
+Arguments:
+  <project>  registered project
+
+Options:
+  -f, --format string  output format (default "sh")
+  -y, --yes            skip the prompt
+`
+	if got := Usage("vunat export", s); got != want {
+		t.Errorf("Usage() =\n%s\nwant\n%s", got, want)
+	}
+	if got := Synopsis("vunat list", Spec{}); got != "vunat list" {
+		t.Errorf("Synopsis() = %q", got)
+	}
+}
@@ -0,0 +1,121 @@
+// Package completion renders shell completion scripts for vunat. The scripts
+// are thin wrappers that call `vunat __complete <words...>` and present its
+// output, so completions always reflect the registered commands, their
+// flags and the current projects registry.
+//
+// Protocol: __complete receives the words after the program name, the last
+// one being the (possibly empty) word under the cursor, and prints one
+// candidate per line as "value" or "value<TAB>description". A single line
+// ":files" asks the shell to complete file names instead.
+package completion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Shells lists the supported shells.
+var Shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Script returns the completion script for shell.
+func Script(shell string) (string, error) {
+	s, ok := scripts[shell]
+	if !ok {
+		names := make([]string, 0, len(scripts))
+		for n := range scripts {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("unsupported shell %q (expected one of: %s)", shell, strings.Join(names, ", "))
+	}
+	return strings.TrimLeft(s, "\n"), nil
+}
+
+var scripts = map[string]string{
+	"bash": `
+# bash completion for vunat.
+# Load it in the current shell with:  source <(vunat completion bash)
+_vunat() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    local line
+    COMPREPLY=()
+    for line in $(vunat __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null); do
+        if [[ $line == ":files" ]]; then
+            COMPREPLY+=($(compgen -f -- "$cur"))
+        else
+            COMPREPLY+=("${line%%$'\t'*}")
+        fi
+    done
+}
+complete -o filenames -F _vunat vunat
+`,
+	"zsh": `
+#compdef vunat
+# zsh completion for vunat.
+# Load it in the current shell with:  source <(vunat completion zsh)
+_vunat() {
+    local -a candidates
+    local line
+    for line in "${(@f)$(vunat __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z $line ]] && continue
+        if [[ $line == ":files" ]]; then
+            _files
+            return
+        fi
+        if [[ $line == *$'\t'* ]]; then
+            candidates+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+        else
+            candidates+=("${line//:/\\:}")
+        fi
+    done
+    _describe 'vunat' candidates
+}
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+    _vunat "$@"
+else
+    compdef _vunat vunat
+fi
+`,
+	"fish": `
+# fish completion for vunat.
+# Load it in the current shell with:  vunat completion fish | source
+function __vunat_complete
+    set -l tokens (commandline -opc)
+    set -l cur (commandline -ct)
+    set -e tokens[1]
+    for line in (vunat __complete $tokens "$cur" 2>/dev/null)
+        if test "$line" = ":files"
+            __fish_complete_path "$cur"
+        else
+            echo $line
+        end
+    end
+end
+complete -c vunat -f -a '(__vunat_complete)'
+`,
+	"powershell": `
+# PowerShell completion for vunat.
+# Load it in the current session with:  vunat completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName vunat -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -eq '') {
+        # Older PowerShell versions drop empty native arguments; vunat treats '""' as empty.
+        $words += '""'
+    }
+    foreach ($line in (& vunat __complete @words 2>$null)) {
+        if ($line -eq ':files') {
+            Get-ChildItem -Path "$wordToComplete*" -ErrorAction SilentlyContinue | ForEach-Object {
+                [System.Management.Automation.CompletionResult]::new($_.Name, $_.Name, 'ProviderItem', $_.FullName)
+            }
+            continue
+        }
+        $parts = $line -split "` + "`" + `t", 2
+        $tip = if ($parts.Count -gt 1 -and $parts[1]) { $parts[1] } else { $parts[0] }
+        [System.Management.Automation.CompletionResult]::new($parts[0], $parts[0], 'ParameterValue', $tip)
+    }
+}
+`,
+}
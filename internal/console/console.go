@@ -0,0 +1,291 @@
+// Package console implements the keyboard controls of `vunat start` in an
+// interactive terminal: single key presses restart processes, show their
+// status, hide a group's output, clear the screen or stop the project, or
+// are passed on to a process that reads input.
+package console
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/runner"
+)
+
+// Key codes read in cbreak mode.
+const (
+	keyEnter  = '\r'
+	keyNL     = '\n'
+	keyEscape = 0x1b
+	keyDelete = 0x7f
+	// keyFocus (Ctrl+]) switches the keys between vunat and a process.
+	keyFocus = 0x1d
+)
+
+// prefix marks the lines written by the controls among process output.
+const prefix = "[vunat] "
+
+// Help is the help overlay listing the key bindings.
+const Help = `
+┌─ Keyboard controls ──────────────────────────┐
+│  r <n>  restart process n (see s)            │
+│  s      show process status                  │
+│  l <n>  hide or show the output of group n   │
+│  i <n>  send the keys to process n           │
+│  Ctrl+] switch the keys between vunat and it │
+│  c      clear the screen                     │
+│  q      stop all processes and quit          │
+│  ?, h   show this help                       │
+│  Esc    cancel a pending r, l or i           │
+└──────────────────────────────────────────────┘
+`
+
+// Project is the running project the controls act on; *runner.Runner
+// implements it.
+type Project interface {
+	Processes() []runner.Process
+	Groups() []string
+	Restart(n int) error
+	Stop() error
+	WriteStatus(w io.Writer) error
+	InputTarget() int
+	WriteInput(n int, p []byte) error
+}
+
+// Controller turns key presses into actions on a running project.
+type Controller struct {
+	runner  Project
+	printer *runner.Printer
+	out     io.Writer
+
+	// pending is the key waiting for a number ('r', 'l' or 'i'), 0 if
+	// none.
+	pending byte
+	digits  string
+	// focus is the number of the process the keys go to, -1 for the
+	// project's stdin target, 0 while they control vunat; last is the
+	// focus before that, for keyFocus.
+	focus, last int
+}
+
+// New returns a Controller acting on r, and on p for hiding output, and
+// writing its messages to out.
+func New(r Project, p *runner.Printer, out io.Writer) *Controller {
+	return &Controller{runner: r, printer: p, out: out}
+}
+
+// FocusInput sends the keys to the process reading vunat's input (see
+// runner.Runner.SetStdinTarget) from the start. Until it runs, and after
+// Ctrl+], they control vunat.
+func (c *Controller) FocusInput() {
+	c.focus = -1
+}
+
+// Run handles keys read from in, one byte at a time, until ctx is done or
+// in is exhausted.
+func (c *Controller) Run(ctx context.Context, in io.Reader) {
+	keys := make(chan byte)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 1)
+		for {
+			if _, err := in.Read(buf); err != nil {
+				return
+			}
+			select {
+			case keys <- buf[0]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	if c.focus != 0 {
+		fmt.Fprintf(c.out, "%sKeys go to the process reading input; press Ctrl+] for vunat's keys (? for help).\n", prefix)
+	} else {
+		fmt.Fprintf(c.out, "%sPress ? for keyboard controls.\n", prefix)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case k, ok := <-keys:
+			if !ok {
+				return
+			}
+			c.key(k)
+		}
+	}
+}
+
+// key handles a single key press.
+func (c *Controller) key(k byte) {
+	if k == keyFocus {
+		c.switchFocus()
+		return
+	}
+	if c.focus != 0 && c.forward(k) {
+		return
+	}
+	if c.pending != 0 {
+		c.number(k)
+		return
+	}
+	switch k {
+	case 'r':
+		c.prompt('r', "Restart which process?", c.processChoices())
+	case 'l':
+		c.prompt('l', "Hide or show the output of which group?", c.groupChoices())
+	case 'i':
+		c.prompt('i', "Send the keys to which process?", c.processChoices())
+	case 's':
+		if err := c.runner.WriteStatus(c.out); err != nil {
+			c.printf("%v", err)
+		}
+	case 'c':
+		// Clear the screen and move the cursor home.
+		fmt.Fprint(c.out, "\x1b[H\x1b[2J")
+	case 'q':
+		c.printf("Stopping all processes (Ctrl+C to kill them)...")
+		go func() {
+			if err := c.runner.Stop(); err != nil {
+				c.printf("%v", err)
+			}
+		}()
+	case '?', 'h':
+		fmt.Fprint(c.out, Help)
+	case keyEnter, keyNL:
+		// Blank lines help to separate output.
+		fmt.Fprintln(c.out)
+	default:
+		c.printf("Unknown key %q; press ? for help.", k)
+	}
+}
+
+// prompt starts reading a number for key, listing the choices.
+func (c *Controller) prompt(key byte, question string, choices []string) {
+	if len(choices) == 0 {
+		c.printf("Nothing to choose from yet.")
+		return
+	}
+	c.pending, c.digits = key, ""
+	fmt.Fprintf(c.out, "%s%s\n", prefix, question)
+	for i, choice := range choices {
+		fmt.Fprintf(c.out, "  %d  %s\n", i+1, choice)
+	}
+	fmt.Fprintf(c.out, "%sType a number (Enter to confirm, Esc to cancel)\n", prefix)
+}
+
+// number adds a key to the pending number and acts once it is complete:
+// on Enter, or as soon as no further digit could make a valid choice.
+func (c *Controller) number(k byte) {
+	max := len(c.processChoices())
+	if c.pending == 'l' {
+		max = len(c.groupChoices())
+	}
+	switch {
+	case k >= '0' && k <= '9':
+		c.digits += string(k)
+		n, _ := strconv.Atoi(c.digits)
+		if n*10 <= max {
+			return
+		}
+	case k == keyDelete && c.digits != "":
+		c.digits = c.digits[:len(c.digits)-1]
+		return
+	case (k == keyEnter || k == keyNL) && c.digits != "":
+	default:
+		c.pending = 0
+		c.printf("Cancelled.")
+		return
+	}
+	key, n := c.pending, c.digits
+	c.pending, c.digits = 0, ""
+	choice, _ := strconv.Atoi(n)
+	switch key {
+	case 'r':
+		go func() {
+			if err := c.runner.Restart(choice); err != nil {
+				c.printf("%v", err)
+			}
+		}()
+		return
+	case 'i':
+		c.attach(choice)
+		return
+	}
+	groups := c.runner.Groups()
+	if choice < 1 || choice > len(groups) {
+		c.printf("No group %d.", choice)
+		return
+	}
+	if c.printer.ToggleGroup(groups[choice-1]) {
+		c.printf("Hiding the output of %s (press l again to show it).", groups[choice-1])
+	} else {
+		c.printf("Showing the output of %s.", groups[choice-1])
+	}
+}
+
+// attach sends the keys to process n from now on.
+func (c *Controller) attach(n int) {
+	procs := c.runner.Processes()
+	if n < 1 || n > len(procs) {
+		c.printf("No process %d.", n)
+		return
+	}
+	p := procs[n-1]
+	if !p.Input {
+		c.printf("[%s] %s does not read input; set \"stdin\": true or use --attach.", p.Group, p.Command)
+		return
+	}
+	c.focus, c.last = n, 0
+	c.printf("Keys go to [%s] %s; press Ctrl+] for vunat's keys.", p.Group, p.Command)
+}
+
+// forward passes k to the focused process and reports whether it did. The
+// keys control vunat while the stdin target has not started, and again if
+// the process cannot take them.
+func (c *Controller) forward(k byte) bool {
+	n := c.focus
+	if n < 0 {
+		if n = c.runner.InputTarget(); n == 0 {
+			return false
+		}
+	}
+	if err := c.runner.WriteInput(n, []byte{k}); err != nil {
+		c.focus, c.last = 0, c.focus
+		c.printf("%v; keys control vunat again (Ctrl+] to retry).", err)
+	}
+	return true
+}
+
+// switchFocus moves the keys from the focused process to vunat, or back.
+func (c *Controller) switchFocus() {
+	switch {
+	case c.focus != 0:
+		c.focus, c.last = 0, c.focus
+		c.printf("Keys control vunat; press Ctrl+] to send them to the process again.")
+	case c.last != 0:
+		c.focus, c.last = c.last, 0
+		c.printf("Keys go to the process again; press Ctrl+] for vunat's keys.")
+	default:
+		c.printf("No process reads the keys yet; press i to choose one.")
+	}
+}
+
+func (c *Controller) processChoices() []string {
+	var out []string
+	for _, p := range c.runner.Processes() {
+		out = append(out, fmt.Sprintf("[%s] %s (%s)", p.Group, p.Command, p.State))
+	}
+	return out
+}
+
+func (c *Controller) groupChoices() []string {
+	return c.runner.Groups()
+}
+
+func (c *Controller) printf(format string, a ...any) {
+	fmt.Fprintln(c.out, prefix+strings.TrimRight(fmt.Sprintf(format, a...), "\n"))
+}
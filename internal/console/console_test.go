@@ -0,0 +1,207 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tanuvnair/vunat-cli/internal/runner"
+)
+
+// fakeProject is a Project with made-up processes that records what the
+// controls ask of it.
+type fakeProject struct {
+	procs    []runner.Process
+	groups   []string
+	target   int
+	writeErr error
+
+	mu       sync.Mutex
+	restarts []int
+	input    []string
+	statuses int
+}
+
+func (f *fakeProject) Processes() []runner.Process { return f.procs }
+func (f *fakeProject) Groups() []string            { return f.groups }
+func (f *fakeProject) InputTarget() int            { return f.target }
+func (f *fakeProject) Stop() error                 { return nil }
+
+func (f *fakeProject) Restart(n int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.restarts = append(f.restarts, n)
+	if n > len(f.procs) {
+		return fmt.Errorf("no process %d", n)
+	}
+	return nil
+}
+
+func (f *fakeProject) WriteStatus(w io.Writer) error {
+	f.mu.Lock()
+	f.statuses++
+	f.mu.Unlock()
+	_, err := io.WriteString(w, "status\n")
+	return err
+}
+
+func (f *fakeProject) WriteInput(n int, p []byte) error {
+	if f.writeErr != nil {
+		return f.writeErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.input = append(f.input, fmt.Sprintf("%d:%s", n, p))
+	return nil
+}
+
+func (f *fakeProject) restarted() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]int(nil), f.restarts...)
+}
+
+// lockedBuffer collects the output of the controls, which restarts write
+// from a goroutine of their own.
+type lockedBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func processes(n int, input ...int) []runner.Process {
+	procs := make([]runner.Process, n)
+	for i := range procs {
+		procs[i] = runner.Process{Number: i + 1, Group: "api", Command: fmt.Sprintf("cmd%d", i+1), State: "running"}
+	}
+	for _, i := range input {
+		procs[i-1].Input = true
+	}
+	return procs
+}
+
+func TestKeys(t *testing.T) {
+	const (
+		esc   = "\x1b"
+		del   = "\x7f"
+		focus = "\x1d"
+	)
+	tests := []struct {
+		name    string
+		project *fakeProject
+		// focusInput starts with the keys going to the stdin target.
+		focusInput bool
+		keys       string
+		restarts   []int
+		input      []string
+		statuses   int
+		want       []string
+	}{
+		{
+			name:    "restart prompt",
+			project: &fakeProject{procs: processes(3)},
+			keys:    "r2",
+			// 2 commits at once: no process 2x exists.
+			restarts: []int{2},
+			want:     []string{"Restart which process?", "  1  [api] cmd1 (running)", "  3  [api] cmd3 (running)", "Type a number"},
+		},
+		{name: "first digit waits for a second", project: &fakeProject{procs: processes(12)}, keys: "r1", want: []string{"Restart which process?"}},
+		{name: "second digit commits", project: &fakeProject{procs: processes(12)}, keys: "r12", restarts: []int{12}},
+		{name: "enter commits", project: &fakeProject{procs: processes(12)}, keys: "r1\r", restarts: []int{1}},
+		{name: "newline commits", project: &fakeProject{procs: processes(12)}, keys: "r1\n", restarts: []int{1}},
+		{name: "digit beyond the choices", project: &fakeProject{procs: processes(12)}, keys: "r13", restarts: []int{13}, want: []string{"no process 13"}},
+		{name: "delete", project: &fakeProject{procs: processes(12)}, keys: "r1" + del + "3", restarts: []int{3}},
+		{name: "escape cancels", project: &fakeProject{procs: processes(12)}, keys: "r1" + esc + "s", statuses: 1, want: []string{"Cancelled."}},
+		{name: "enter without digits cancels", project: &fakeProject{procs: processes(3)}, keys: "r\r", want: []string{"Cancelled."}},
+		{name: "nothing to choose", keys: "r", want: []string{"Nothing to choose from yet."}},
+		{name: "unknown key", keys: "x", want: []string{`Unknown key 'x'`}},
+		{
+			name:    "toggle group output",
+			project: &fakeProject{groups: []string{"api", "web"}},
+			keys:    "l2l2",
+			want:    []string{"Hide or show the output of which group?", "  2  web", "Hiding the output of web", "Showing the output of web."},
+		},
+		{
+			name:    "attach and switch focus",
+			project: &fakeProject{procs: processes(3, 2)},
+			keys:    "i2ab" + focus + "s" + focus + "c",
+			input:   []string{"2:a", "2:b", "2:c"},
+			want:    []string{"Keys go to [api] cmd2", "Keys control vunat;", "Keys go to the process again"},
+			// s reached vunat between the two switches.
+			statuses: 1,
+		},
+		{name: "attach to a process without input", project: &fakeProject{procs: processes(3, 2)}, keys: "i3s", statuses: 1, want: []string{"[api] cmd3 does not read input"}},
+		{name: "switch focus with nothing attached", keys: focus, want: []string{"No process reads the keys yet"}},
+		{
+			name:       "stdin target",
+			project:    &fakeProject{procs: processes(3, 3), target: 3},
+			focusInput: true,
+			keys:       "r" + focus + "s",
+			input:      []string{"3:r"},
+			statuses:   1,
+		},
+		{name: "stdin target not started", focusInput: true, keys: "s", statuses: 1},
+		{
+			name:     "write fails",
+			project:  &fakeProject{procs: processes(3, 2), writeErr: errors.New("[api] cmd2 has exited")},
+			keys:     "i2as" + focus,
+			statuses: 1,
+			want:     []string{"[api] cmd2 has exited; keys control vunat again (Ctrl+] to retry).", "Keys go to the process again"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.project
+			if f == nil {
+				f = &fakeProject{}
+			}
+			var out lockedBuffer
+			c := New(f, runner.NewPrinter(io.Discard, io.Discard), &out)
+			if tt.focusInput {
+				c.FocusInput()
+			}
+			for _, k := range []byte(tt.keys) {
+				c.key(k)
+			}
+
+			// Restarts run in the background.
+			deadline := time.Now().Add(5 * time.Second)
+			for len(f.restarted()) < len(tt.restarts) && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			if got := f.restarted(); !reflect.DeepEqual(got, tt.restarts) && len(got)+len(tt.restarts) > 0 {
+				t.Errorf("restarts = %v, want %v", got, tt.restarts)
+			}
+			if !reflect.DeepEqual(f.input, tt.input) {
+				t.Errorf("input = %q, want %q", f.input, tt.input)
+			}
+			if f.statuses != tt.statuses {
+				t.Errorf("status shown %d times, want %d", f.statuses, tt.statuses)
+			}
+			for _, want := range tt.want {
+				for !strings.Contains(out.String(), want) && time.Now().Before(deadline) {
+					time.Sleep(time.Millisecond)
+				}
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output is missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
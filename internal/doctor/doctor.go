@@ -0,0 +1,255 @@
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/config"
+	"github.com/tanuvnair/vunat-cli/internal/launcher"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// Status is the outcome of a single check.
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+// MarshalText encodes the status as its lowercase name for JSON/YAML output.
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "pass"
+	case Warn:
+		return "warn"
+	default:
+		return "fail"
+	}
+}
+
+// Result describes the outcome of one diagnostic check.
+type Result struct {
+	Check  string `json:"check"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Checker runs environment diagnostics against the config managed by Config.
+// The function fields default to their os/exec/net counterparts and exist so
+// the checks can be exercised without touching the real environment.
+type Checker struct {
+	Config   config.Manager
+	Getenv   func(string) string
+	LookPath func(string) (string, error)
+	// PortFree reports whether a TCP port can currently be bound.
+	PortFree func(port int) bool
+}
+
+// New constructs a Checker using the real environment.
+func New(cfg config.Manager) *Checker {
+	return &Checker{
+		Config:   cfg,
+		Getenv:   os.Getenv,
+		LookPath: exec.LookPath,
+		PortFree: portFree,
+	}
+}
+
+// Run performs all checks. If project is non-empty, only that project's
+// directories, executables and ports are inspected.
+func (c *Checker) Run(project string) []Result {
+	var results []Result
+	add := func(check string, status Status, format string, args ...any) {
+		results = append(results, Result{Check: check, Status: status, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	conf, err := c.readConfig()
+	if err != nil {
+		add("config", Fail, "%v", err)
+	} else {
+		add("config", Pass, "%s is readable (%d project(s))", c.Config.Path(), len(conf.Projects))
+	}
+
+	names := make([]string, 0, len(conf.Projects))
+	for name := range conf.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if project != "" && err == nil {
+		if name, err := conf.Resolve(project); err != nil {
+			add("project", Fail, "%v", err)
+			names = nil
+		} else {
+			names = []string{name}
+		}
+	}
+
+	ports := make(map[int]string)
+	for _, name := range names {
+		for _, group := range conf.Projects[name].Groups {
+			label := fmt.Sprintf("%s/%s", name, group.Name)
+			dirOK := true
+			if group.AbsolutePath != "" {
+				if info, err := os.Stat(group.AbsolutePath); err != nil {
+					add("path", Fail, "%s: %s does not exist", label, group.AbsolutePath)
+					dirOK = false
+				} else if !info.IsDir() {
+					add("path", Fail, "%s: %s is not a directory", label, group.AbsolutePath)
+					dirOK = false
+				} else {
+					add("path", Pass, "%s: %s", label, group.AbsolutePath)
+				}
+			}
+			for _, cmd := range group.Commands {
+				if !dirOK {
+					break
+				}
+				fields, err := projects.SplitArgs(cmd.Command)
+				if err != nil {
+					add("executable", Fail, "%s: %v", label, err)
+					continue
+				}
+				if len(fields) == 0 {
+					continue
+				}
+				if path, err := c.resolve(group.AbsolutePath, fields[0]); err != nil {
+					add("executable", Fail, "%s: %q: %v", label, fields[0], err)
+				} else {
+					add("executable", Pass, "%s: %s -> %s", label, fields[0], path)
+				}
+			}
+			for _, port := range groupPorts(group) {
+				if _, seen := ports[port]; !seen {
+					ports[port] = label
+				}
+			}
+		}
+	}
+
+	portList := make([]int, 0, len(ports))
+	for p := range ports {
+		portList = append(portList, p)
+	}
+	sort.Ints(portList)
+	for _, p := range portList {
+		if c.PortFree(p) {
+			add("port", Pass, "%s: port %d is free", ports[p], p)
+		} else {
+			add("port", Fail, "%s: port %d is already in use", ports[p], p)
+		}
+	}
+
+	results = append(results, c.checkOpener())
+	results = append(results, c.checkInstallDir())
+	return results
+}
+
+func (c *Checker) readConfig() (projects.Config, error) {
+	if c.Config == nil {
+		return projects.Config{}, fmt.Errorf("config manager not provided")
+	}
+	data, err := c.Config.Read()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return projects.Config{}, fmt.Errorf("%s does not exist; run `vunat config` or `vunat init` to create it", c.Config.Path())
+		}
+		return projects.Config{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return projects.Parse(data)
+}
+
+// resolve finds the executable for a command the way the runner would start
+// it from dir: names containing a path separator are relative to dir, bare
+// names are looked up on PATH. Windows has no execute permission, so there
+// paths are resolved by LookPath too, which also tries the PATHEXT
+// extensions.
+func (c *Checker) resolve(dir, name string) (string, error) {
+	if !strings.ContainsRune(name, '/') && !strings.ContainsRune(name, filepath.Separator) {
+		return c.LookPath(name)
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if runtime.GOOS == "windows" {
+		resolved, err := c.LookPath(path)
+		if err != nil {
+			return "", fmt.Errorf("%s does not exist or is not executable", path)
+		}
+		return resolved, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("%s does not exist", path)
+	}
+	if info.IsDir() || info.Mode()&0o111 == 0 {
+		return "", fmt.Errorf("%s is not executable", path)
+	}
+	return path, nil
+}
+
+// checkOpener verifies that `vunat config` has a way to open the file:
+// $EDITOR when set, otherwise the platform opener.
+func (c *Checker) checkOpener() Result {
+	if editor := strings.Fields(c.Getenv("EDITOR")); len(editor) > 0 {
+		if path, err := c.LookPath(editor[0]); err == nil {
+			return Result{Check: "editor", Status: Pass, Detail: fmt.Sprintf("$EDITOR resolves to %s", path)}
+		}
+		return Result{Check: "editor", Status: Fail, Detail: fmt.Sprintf("$EDITOR is %q but %s was not found on PATH", c.Getenv("EDITOR"), editor[0])}
+	}
+	opener := launcher.OpenerName()
+	if path, err := c.LookPath(opener); err == nil {
+		return Result{Check: "editor", Status: Pass, Detail: fmt.Sprintf("$EDITOR not set; `vunat config` will use %s", path)}
+	}
+	return Result{Check: "editor", Status: Warn, Detail: fmt.Sprintf("$EDITOR not set and %s not found; `vunat config` cannot open the file", opener)}
+}
+
+// checkInstallDir verifies that vunat can be invoked from any shell.
+func (c *Checker) checkInstallDir() Result {
+	if path, err := c.LookPath("vunat"); err == nil {
+		return Result{Check: "install", Status: Pass, Detail: fmt.Sprintf("vunat found on PATH at %s", path)}
+	}
+	if c.Config == nil {
+		return Result{Check: "install", Status: Warn, Detail: "vunat not found on PATH; see the README install section"}
+	}
+	installDir := filepath.Dir(c.Config.Path())
+	for _, dir := range filepath.SplitList(c.Getenv("PATH")) {
+		if filepath.Clean(dir) == filepath.Clean(installDir) {
+			return Result{Check: "install", Status: Warn, Detail: fmt.Sprintf("%s is on PATH but contains no vunat binary", installDir)}
+		}
+	}
+	return Result{Check: "install", Status: Warn, Detail: fmt.Sprintf("%s is not on PATH; see the README install section", installDir)}
+}
+
+// groupPorts returns the ports a group declares via "ports" or a PORT
+// environment variable.
+func groupPorts(g projects.CommandGroup) []int {
+	ports := append([]int(nil), g.Ports...)
+	if p, err := strconv.Atoi(g.Env["PORT"]); err == nil && p > 0 {
+		ports = append(ports, p)
+	}
+	return ports
+}
+
+func portFree(port int) bool {
+	l, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return false
+	}
+	_ = l.Close()
+	return true
+}
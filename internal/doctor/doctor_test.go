@@ -0,0 +1,110 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/tanuvnair/vunat-cli/internal/config"
+)
+
+func TestResolve(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("execute permissions are not checked on Windows")
+	}
+	dir := t.TempDir()
+	for name, mode := range map[string]os.FileMode{"run.sh": 0o755, "data.txt": 0o644} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &Checker{LookPath: func(name string) (string, error) {
+		if name == "go" {
+			return "/usr/bin/go", nil
+		}
+		return "", errors.New("not found")
+	}}
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "go", want: "/usr/bin/go"},
+		{name: "node", wantErr: true},
+		{name: "./run.sh", want: filepath.Join(dir, "run.sh")},
+		{name: filepath.Join(dir, "run.sh"), want: filepath.Join(dir, "run.sh")},
+		{name: "./data.txt", wantErr: true},
+		{name: "./missing", wantErr: true},
+		{name: ".", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := c.resolve(dir, tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("resolve(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRunWithoutConfig(t *testing.T) {
+	c := &Checker{
+		Getenv:   func(string) string { return "" },
+		LookPath: func(string) (string, error) { return "", errors.New("not found") },
+		PortFree: func(int) bool { return true },
+	}
+	results := c.Run("")
+	if len(results) == 0 || results[0].Check != "config" || results[0].Status != Fail {
+		t.Fatalf("Run() = %+v, want a failed config check first", results)
+	}
+	last := results[len(results)-1]
+	if last.Check != "install" || last.Status != Warn {
+		t.Errorf("install check = %+v, want a warning", last)
+	}
+}
+
+func TestRunProjectName(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.NewFSManager(filepath.Join(dir, "config.json"))
+	data := fmt.Sprintf(`{"projects": {
+		"shop": [{"name": "api", "absolutePath": %[1]q, "commands": ["go run ."]}],
+		"shopfront": [{"name": "web", "absolutePath": %[1]q, "commands": ["go run ."]}],
+		"blog": [{"name": "web", "absolutePath": %[1]q, "commands": ["go run ."]}]
+	}}`, dir)
+	if err := os.WriteFile(cfg.Path(), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := &Checker{
+		Config:   cfg,
+		Getenv:   func(string) string { return "" },
+		LookPath: func(string) (string, error) { return "/usr/bin/go", nil },
+		PortFree: func(int) bool { return true },
+	}
+	tests := []struct {
+		project string
+		// want is the detail of the failed project check, or the label
+		// of the only group checked.
+		want string
+	}{
+		{project: "shop", want: "shop/api"},
+		{project: "b", want: "blog/web"},
+		{project: "sho", want: `ambiguous project "sho": matches shop, shopfront`},
+		{project: "blgo", want: `unknown project "blgo", did you mean "blog"?`},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range c.Run(tt.project) {
+			switch {
+			case r.Check == "project" && r.Status == Fail:
+				got = append(got, r.Detail)
+			case r.Check == "path":
+				got = append(got, strings.SplitN(r.Detail, ":", 2)[0])
+			}
+		}
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("Run(%q) reported %q, want %q", tt.project, got, tt.want)
+		}
+	}
+}
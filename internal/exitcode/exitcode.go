@@ -0,0 +1,107 @@
+// Package exitcode defines vunat's exit statuses and the error types that
+// select them, so every layer can report what kind of failure occurred
+// without depending on the CLI.
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// Exit statuses of the vunat binary.
+const (
+	OK = 0
+	// Error is any failure without a more specific status.
+	Error = 1
+	// Usage means the command line was invalid.
+	Usage = 2
+	// Config means the config file could not be read or is invalid.
+	Config = 3
+	// ProcessFailed means a process supervised by `vunat start` (a service,
+	// oneshot or hook) failed.
+	ProcessFailed = 4
+	// Interrupted is the status after Ctrl+C; other signals use 128+signal
+	// as shells do.
+	Interrupted = 130
+)
+
+// ConfigError reports a config file that cannot be read or is invalid.
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+func (e *ConfigError) Unwrap() error { return e.Err }
+func (e *ConfigError) ExitCode() int { return Config }
+
+// ProcessError reports a supervised process that failed to start, exited
+// non-zero or was killed by a signal.
+type ProcessError struct {
+	// Process identifies the process, e.g. `[api] go run ./cmd/api`.
+	Process string
+	// Code is the exit code, or -1 if the process did not exit normally.
+	Code int
+	// Signal names the signal that killed the process, if any.
+	Signal string
+	Err    error
+}
+
+// NewProcessError describes err, as returned by exec.Cmd.Wait or Start, for
+// process.
+func NewProcessError(process string, err error) *ProcessError {
+	e := &ProcessError{Process: process, Code: -1, Err: err}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		e.Code = exitErr.ExitCode()
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			e.Signal = ws.Signal().String()
+		}
+	}
+	return e
+}
+
+func (e *ProcessError) Error() string {
+	switch {
+	case e.Signal != "":
+		return fmt.Sprintf("%s was killed by signal: %s", e.Process, e.Signal)
+	case e.Code >= 0:
+		return fmt.Sprintf("%s exited with status %d", e.Process, e.Code)
+	}
+	return fmt.Sprintf("%s failed: %v", e.Process, e.Err)
+}
+
+func (e *ProcessError) Unwrap() error { return e.Err }
+func (e *ProcessError) ExitCode() int { return ProcessFailed }
+
+// InterruptError reports that the user stopped vunat with a signal. It is
+// not an error as such, so the CLI prints nothing for it.
+type InterruptError struct {
+	Signal os.Signal
+}
+
+func (e *InterruptError) Error() string { return "interrupted" }
+
+func (e *InterruptError) ExitCode() int {
+	if s, ok := e.Signal.(syscall.Signal); ok && s != syscall.SIGINT {
+		return 128 + int(s)
+	}
+	return Interrupted
+}
+
+// Of returns the exit status for err: OK for nil, the status chosen by the
+// first error in its chain with an ExitCode method (such as the errors of
+// this package or *exec.ExitError for commands that pass a child's status
+// through), and Error otherwise.
+func Of(err error) int {
+	if err == nil {
+		return OK
+	}
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) && coder.ExitCode() > 0 {
+		return coder.ExitCode()
+	}
+	return Error
+}
@@ -0,0 +1,81 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"testing"
+)
+
+// codeError is an error with its own exit status.
+type codeError int
+
+func (e codeError) Error() string { return fmt.Sprintf("code %d", int(e)) }
+func (e codeError) ExitCode() int { return int(e) }
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: OK},
+		{name: "plain", err: errors.New("boom"), want: Error},
+		{name: "config", err: &ConfigError{Err: errors.New("bad json")}, want: Config},
+		{name: "wrapped config", err: fmt.Errorf("loading: %w", &ConfigError{Err: errors.New("bad json")}), want: Config},
+		{name: "process", err: &ProcessError{Process: "[api] x", Code: 1}, want: ProcessFailed},
+		{name: "interrupt", err: &InterruptError{Signal: os.Interrupt}, want: Interrupted},
+		{name: "interrupt without signal", err: &InterruptError{}, want: Interrupted},
+		{name: "terminated", err: &InterruptError{Signal: syscall.SIGTERM}, want: 128 + int(syscall.SIGTERM)},
+		{name: "passed through", err: fmt.Errorf("task: %w", codeError(7)), want: 7},
+		{name: "first in chain", err: &ConfigError{Err: codeError(7)}, want: Config},
+		{name: "zero status", err: codeError(0), want: Error},
+		{name: "negative status", err: codeError(-1), want: Error},
+	}
+	for _, tt := range tests {
+		if got := Of(tt.err); got != tt.want {
+			t.Errorf("%s: Of(%v) = %d, want %d", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNewProcessError(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	tests := []struct {
+		name    string
+		cmd     *exec.Cmd
+		code    int
+		signal  bool
+		message string
+	}{
+		{name: "exit status", cmd: exec.Command("sh", "-c", "exit 7"), code: 7, message: "[app] test exited with status 7"},
+		{name: "not found", cmd: exec.Command("vunat-test-no-such-command"), code: -1},
+		{name: "signal", cmd: exec.Command("sh", "-c", "kill -KILL $$"), code: -1, signal: true, message: "[app] test was killed by signal: killed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.signal && runtime.GOOS == "windows" {
+				t.Skip("no signals on Windows")
+			}
+			err := tt.cmd.Run()
+			if err == nil {
+				t.Fatal("command succeeded")
+			}
+			e := NewProcessError("[app] test", err)
+			if e.Code != tt.code || (e.Signal != "") != tt.signal {
+				t.Errorf("NewProcessError() = code %d, signal %q; want %d, signal %v", e.Code, e.Signal, tt.code, tt.signal)
+			}
+			if tt.message != "" && e.Error() != tt.message {
+				t.Errorf("Error() = %q, want %q", e.Error(), tt.message)
+			}
+			if !errors.Is(e, err) || Of(e) != ProcessFailed {
+				t.Errorf("error chain or status wrong: %v, %d", e, Of(e))
+			}
+		})
+	}
+}
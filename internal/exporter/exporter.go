@@ -0,0 +1,555 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// Formats lists the supported export formats.
+var Formats = []string{"procfile", "compose", "sh", "systemd"}
+
+// process is a single command of a project flattened together with the group
+// settings it runs with.
+type process struct {
+	// id is a unique, filename-safe identifier derived from the group name.
+	id      string
+	group   string
+	dir     string
+	command string
+	env     map[string]string
+	// oneshot marks a setup step that must finish before later processes.
+	oneshot bool
+	// after lists the ids of the processes started by the previous group.
+	after []string
+
+	hooks processHooks
+}
+
+// processHooks are the hooks that run around a process:
+//   - before, when it is the first process of its group (and of the project);
+//   - beforeEach, right before it starts;
+//   - after, once the rest of its group has started, when it is the last;
+//   - afterStop, once its group has stopped, when it is the first.
+type processHooks struct {
+	before, beforeEach, after, afterStop []hook
+}
+
+func (h processHooks) all() []hook {
+	return slices.Concat(h.before, h.beforeEach, h.after, h.afterStop)
+}
+
+// hook is a hook command together with the settings it runs with.
+type hook struct {
+	// label prefixes its output, e.g. "[api:before] ".
+	label   string
+	dir     string
+	env     map[string]string
+	command string
+	// abort stops the project when the hook fails (hookFailure: abort).
+	abort bool
+}
+
+// hasHooks reports whether any process of procs runs hooks.
+func hasHooks(procs []process) bool {
+	for _, p := range procs {
+		if len(p.hooks.all()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Export renders project name in the given format to w. Groups keep their
+// order: formats that support dependencies express "start after the previous
+// group", the others emit processes in start order.
+func Export(w io.Writer, name string, proj projects.Project, format string) error {
+	procs := flatten(proj)
+	if len(procs) == 0 {
+		return fmt.Errorf("project %q has no commands to export", name)
+	}
+	// Nothing is written for a project vunat itself could not start.
+	for _, p := range procs {
+		if _, err := projects.SplitArgs(p.command); err != nil {
+			return fmt.Errorf("group %q: %w", p.group, err)
+		}
+		for _, h := range p.hooks.all() {
+			if _, err := projects.SplitArgs(h.command); err != nil {
+				return fmt.Errorf("%s hook: %w", strings.Trim(h.label, "[] "), err)
+			}
+		}
+	}
+	switch format {
+	case "procfile", "compose":
+		if hasHooks(procs) {
+			return fmt.Errorf("project %q has hooks, which the %s format cannot run in order; export it as sh or systemd instead", name, format)
+		}
+	}
+	switch format {
+	case "procfile":
+		return writeProcfile(w, name, procs)
+	case "compose":
+		return writeCompose(w, procs)
+	case "sh":
+		return writeShell(w, name, procs)
+	case "systemd":
+		return writeSystemd(w, name, procs)
+	}
+	return fmt.Errorf("unknown export format %q (expected one of: %s)", format, strings.Join(Formats, ", "))
+}
+
+// flatten lists the processes of proj in start order. A group's oneshot
+// commands come first and its services start after them. The hooks of a
+// group without commands run with those of the next process, or after the
+// last one.
+func flatten(proj projects.Project) []process {
+	var procs []process
+	used := make(map[string]bool)
+	var prev []string
+	var projDir string
+	var projEnv map[string]string
+	if len(proj.Groups) > 0 {
+		projDir, projEnv = proj.Groups[0].AbsolutePath, proj.Groups[0].Env
+	}
+	projAbort := proj.HookFailure != projects.HookContinue
+	hooksOf := func(scope, kind, dir string, env map[string]string, abort bool, cmds []string) []hook {
+		var out []hook
+		for _, c := range cmds {
+			out = append(out, hook{label: fmt.Sprintf("[%s:%s] ", scope, kind), dir: dir, env: env, command: c, abort: abort})
+		}
+		return out
+	}
+	// pending and pendingStop hold the hooks of groups without commands.
+	pending := hooksOf("project", "before", projDir, projEnv, projAbort, proj.Before)
+	var pendingStop []hook
+	for _, group := range proj.Groups {
+		var ids, setup []string
+		var services []process
+		commands := make([]projects.Command, 0, len(group.Commands))
+		for _, c := range group.Commands {
+			if c.Command = strings.TrimSpace(c.Command); c.Command != "" {
+				commands = append(commands, c)
+			}
+		}
+		abort := projAbort
+		if group.HookFailure != "" {
+			abort = group.HookFailure != projects.HookContinue
+		}
+		pending = append(pending, hooksOf(group.Name, "before", group.AbsolutePath, group.Env, abort, group.Before)...)
+		after := hooksOf(group.Name, "after", group.AbsolutePath, group.Env, abort, group.After)
+		// Groups stop in reverse order.
+		pendingStop = append(hooksOf(group.Name, "afterStop", group.AbsolutePath, group.Env, false, group.AfterStop), pendingStop...)
+		if len(commands) == 0 {
+			pending = append(pending, after...)
+			continue
+		}
+		beforeEach := slices.Concat(
+			hooksOf("project", "beforeEach", group.AbsolutePath, group.Env, projAbort, proj.BeforeEach),
+			hooksOf(group.Name, "beforeEach", group.AbsolutePath, group.Env, abort, group.BeforeEach),
+		)
+		for i, c := range commands {
+			base := sanitize(group.Name)
+			if len(commands) > 1 {
+				base = fmt.Sprintf("%s-%d", base, i+1)
+			}
+			id := base
+			for n := 2; used[id]; n++ {
+				id = fmt.Sprintf("%s-%d", base, n)
+			}
+			used[id] = true
+			ids = append(ids, id)
+			p := process{
+				id:      id,
+				group:   group.Name,
+				dir:     group.AbsolutePath,
+				command: c.Command,
+				env:     group.Env,
+				oneshot: c.Oneshot(),
+				after:   prev,
+
+				hooks: processHooks{beforeEach: beforeEach},
+			}
+			if !p.oneshot {
+				services = append(services, p)
+				continue
+			}
+			procs = append(procs, p)
+			setup = append(setup, id)
+		}
+		for _, p := range services {
+			if len(setup) > 0 {
+				p.after = setup
+			}
+			procs = append(procs, p)
+		}
+		first := &procs[len(procs)-len(commands)]
+		first.hooks.before, first.hooks.afterStop = pending, pendingStop
+		procs[len(procs)-1].hooks.after = after
+		pending, pendingStop = nil, nil
+		prev = ids
+	}
+	if len(procs) == 0 {
+		return nil
+	}
+	last := &procs[len(procs)-1]
+	last.hooks.after = slices.Concat(last.hooks.after, pending, hooksOf("project", "after", projDir, projEnv, projAbort, proj.After))
+	last.hooks.afterStop = append(pendingStop, last.hooks.afterStop...)
+	procs[0].hooks.afterStop = append(procs[0].hooks.afterStop, hooksOf("project", "afterStop", projDir, projEnv, false, proj.AfterStop)...)
+	return procs
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// sanitize turns a group name into an identifier accepted by every format
+// (Procfile process types, compose service names, systemd unit names).
+func sanitize(name string) string {
+	s := strings.Trim(unsafeChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if s == "" {
+		return "process"
+	}
+	return s
+}
+
+func writeProcfile(w io.Writer, name string, procs []process) error {
+	fmt.Fprintf(w, "# Generated by vunat export from project %q.\n", name)
+	fmt.Fprintln(w, "# Processes are listed in start order; Procfile runners start them together.")
+	for _, p := range procs {
+		fmt.Fprintf(w, "%s: %s\n", p.id, shellLine(p))
+	}
+	return nil
+}
+
+func writeCompose(w io.Writer, procs []process) error {
+	oneshot := make(map[string]bool)
+	for _, p := range procs {
+		oneshot[p.id] = p.oneshot
+	}
+	fmt.Fprintln(w, "# vunat runs these commands on the host. Here each one runs in an image")
+	fmt.Fprintln(w, "# guessed from its command, with its directory mounted at /app; change")
+	fmt.Fprintln(w, "# image: where the guess lacks the tools it needs.")
+	fmt.Fprintln(w, "services:")
+	for _, p := range procs {
+		args, _ := projects.SplitArgs(p.command) // checked by Export
+		fmt.Fprintf(w, "  %s:\n", p.id)
+		fmt.Fprintf(w, "    image: %s\n", composeQuote(imageFor(args[0])))
+		if p.dir != "" {
+			fmt.Fprintln(w, "    working_dir: /app")
+			fmt.Fprintln(w, "    volumes:")
+			fmt.Fprintf(w, "      - %s\n", composeQuote(p.dir+":/app"))
+		}
+		fmt.Fprintln(w, "    command:")
+		for _, arg := range args {
+			fmt.Fprintf(w, "      - %s\n", composeQuote(arg))
+		}
+		if len(p.env) > 0 {
+			fmt.Fprintln(w, "    environment:")
+			for _, k := range sortedKeys(p.env) {
+				fmt.Fprintf(w, "      %s: %s\n", strconv.Quote(k), composeQuote(p.env[k]))
+			}
+		}
+		if p.oneshot {
+			fmt.Fprintln(w, `    restart: "no"`)
+		}
+		if len(p.after) > 0 {
+			fmt.Fprintln(w, "    depends_on:")
+			for _, dep := range p.after {
+				condition := "service_started"
+				if oneshot[dep] {
+					condition = "service_completed_successfully"
+				}
+				fmt.Fprintf(w, "      %s:\n        condition: %s\n", dep, condition)
+			}
+		}
+	}
+	return nil
+}
+
+// composeQuote quotes s as a YAML string that compose does not interpolate:
+// $VAR is left for the process to expand at run time.
+func composeQuote(s string) string {
+	return strconv.Quote(strings.ReplaceAll(s, "$", "$$"))
+}
+
+// images maps the program a command starts to an image providing it.
+var images = map[string]string{
+	"node":    "node:lts",
+	"npm":     "node:lts",
+	"npx":     "node:lts",
+	"yarn":    "node:lts",
+	"pnpm":    "node:lts",
+	"go":      "golang:1",
+	"python":  "python:3",
+	"python3": "python:3",
+	"pip":     "python:3",
+	"cargo":   "rust:1",
+	"ruby":    "ruby:3",
+	"bundle":  "ruby:3",
+	"rails":   "ruby:3",
+}
+
+// imageFor returns the image a compose service running program uses.
+func imageFor(program string) string {
+	if image, ok := images[path.Base(program)]; ok {
+		return image
+	}
+	return "alpine:3"
+}
+
+func writeShell(w io.Writer, name string, procs []process) error {
+	fmt.Fprintln(w, "#!/bin/sh")
+	fmt.Fprintf(w, "# Generated by vunat export from project %q.\n", name)
+	fmt.Fprintln(w, "# Runs oneshot commands to completion, starts every other process in the")
+	fmt.Fprintln(w, "# background with its output prefixed by the group name, and stops them all")
+	fmt.Fprintln(w, "# when the script is interrupted or a oneshot fails.")
+	fmt.Fprintln(w, "set -e")
+	// afterStop hooks run once everything has been stopped; the script
+	// ignores the TERM it sends its process group.
+	afterStop := afterStopHooks(procs)
+	if len(afterStop) == 0 {
+		fmt.Fprintln(w, "trap 'trap - INT TERM EXIT; kill 0' INT TERM EXIT")
+	}
+	fmt.Fprintln(w)
+	io.WriteString(w, `prefix() { while IFS= read -r line; do printf '%s%s\n' "$1" "$line"; done; }`+"\n")
+	fmt.Fprintln(w)
+	if len(afterStop) > 0 {
+		fmt.Fprintln(w, "stop() {")
+		fmt.Fprintln(w, "\ttrap - INT TERM EXIT")
+		fmt.Fprintln(w, "\ttrap '' TERM")
+		fmt.Fprintln(w, "\tkill 0")
+		fmt.Fprintln(w, "\twait || true")
+		fmt.Fprintln(w, "\ttrap - TERM")
+		for _, h := range afterStop {
+			fmt.Fprintf(w, "\t%s\n", shellHook(h))
+		}
+		fmt.Fprintln(w, "\texit \"$1\"")
+		fmt.Fprintln(w, "}")
+		fmt.Fprintln(w, "trap 'stop 130' INT")
+		fmt.Fprintln(w, "trap 'stop 143' TERM")
+		fmt.Fprintln(w, "trap 'stop $?' EXIT")
+		fmt.Fprintln(w)
+	}
+	group := ""
+	var last process
+	for _, p := range procs {
+		if p.group != group {
+			if group != "" {
+				writeShellStarted(w, last)
+			}
+			group = p.group
+			fmt.Fprintf(w, "echo %s\n", shellQuote(fmt.Sprintf("[%s] Starting in: %s", p.group, p.dir)))
+		}
+		for _, h := range slices.Concat(p.hooks.before, p.hooks.beforeEach) {
+			fmt.Fprintln(w, shellHook(h))
+		}
+		last = p
+		if p.oneshot {
+			fmt.Fprintf(w, "( (%s) 2>&1 || kill 0 ) | prefix %s\n", shellLine(p), shellQuote("["+p.group+"] "))
+			continue
+		}
+		fmt.Fprintf(w, "(%s) 2>&1 | prefix %s &\n", shellLine(p), shellQuote("["+p.group+"] "))
+	}
+	writeShellStarted(w, last)
+	fmt.Fprintln(w, "wait")
+	return nil
+}
+
+// writeShellStarted ends the group of p, its last process, by reporting it
+// started and running its after hooks.
+func writeShellStarted(w io.Writer, p process) {
+	fmt.Fprintf(w, "echo %s\n", shellQuote("["+p.group+"] Started"))
+	for _, h := range p.hooks.after {
+		fmt.Fprintln(w, shellHook(h))
+	}
+	fmt.Fprintln(w)
+}
+
+// afterStopHooks returns the afterStop hooks of procs in the order they
+// run: groups in reverse order, then the project.
+func afterStopHooks(procs []process) []hook {
+	var hooks []hook
+	for i := len(procs) - 1; i >= 0; i-- {
+		for _, h := range procs[i].hooks.afterStop {
+			h.abort = false
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks
+}
+
+// shellHook renders h as a command run to completion; when it fails the
+// script stops if h aborts and carries on otherwise.
+func shellHook(h hook) string {
+	onFailure := "true"
+	if h.abort {
+		onFailure = "kill 0"
+	}
+	line := shellLine(process{dir: h.dir, env: h.env, command: h.command})
+	return fmt.Sprintf("( (%s) 2>&1 || %s ) | prefix %s", line, onFailure, shellQuote(h.label))
+}
+
+func writeSystemd(w io.Writer, name string, procs []process) error {
+	target := "vunat-" + sanitize(name)
+	fmt.Fprintf(w, "# Generated by vunat export from project %q.\n", name)
+	fmt.Fprintf(w, "# Save each unit below under ~/.config/systemd/user/ and run:\n")
+	fmt.Fprintf(w, "#   systemctl --user daemon-reload && systemctl --user start %s.target\n", target)
+	// Hooks run as part of the units, which then start one after another
+	// as vunat starts them and stop in reverse order. afterStop hooks get
+	// a unit of their own that stops after all the others, so they do not
+	// run when a unit restarts.
+	chain := false
+	for _, p := range procs {
+		chain = chain || len(p.hooks.before)+len(p.hooks.beforeEach)+len(p.hooks.after) > 0
+	}
+	afterStop := afterStopHooks(procs)
+	stopUnit := target + ".afterstop.service"
+	if chain {
+		fmt.Fprintln(w, "# Hooks run as ExecStartPre/ExecStartPost steps, again when a unit restarts.")
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "# --- %s.target ---\n", target)
+	fmt.Fprintln(w, "[Unit]")
+	fmt.Fprintf(w, "Description=vunat project %s\n", name)
+	units := make([]string, 0, len(procs))
+	for _, p := range procs {
+		units = append(units, target+"-"+p.id+".service")
+	}
+	wants := units
+	if len(afterStop) > 0 {
+		wants = append([]string{stopUnit}, units...)
+	}
+	fmt.Fprintf(w, "Wants=%s\n", strings.Join(wants, " "))
+
+	if len(afterStop) > 0 {
+		fmt.Fprintf(w, "\n# --- %s ---\n", stopUnit)
+		fmt.Fprintln(w, "[Unit]")
+		fmt.Fprintf(w, "Description=afterStop hooks of vunat project %s\n", systemdEscape(name))
+		fmt.Fprintf(w, "PartOf=%s.target\n", target)
+		fmt.Fprintf(w, "Before=%s\n", strings.Join(units, " "))
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "[Service]")
+		fmt.Fprintln(w, "Type=oneshot")
+		fmt.Fprintln(w, "RemainAfterExit=yes")
+		fmt.Fprintln(w, "ExecStart=/bin/true")
+		for _, h := range afterStop {
+			fmt.Fprintf(w, "ExecStop=%s\n", systemdHook(h))
+		}
+	}
+
+	for i, p := range procs {
+		unit := target + "-" + p.id
+		fmt.Fprintf(w, "\n# --- %s.service ---\n", unit)
+		fmt.Fprintln(w, "[Unit]")
+		fmt.Fprintf(w, "Description=%s\n", systemdEscape(p.command+" ("+p.group+")"))
+		fmt.Fprintf(w, "PartOf=%s.target\n", target)
+		after := p.after
+		if chain && i > 0 && !slices.Contains(after, procs[i-1].id) {
+			after = append(slices.Clip(after), procs[i-1].id)
+		}
+		if len(after) > 0 {
+			deps := make([]string, 0, len(after))
+			for _, dep := range after {
+				deps = append(deps, target+"-"+dep+".service")
+			}
+			fmt.Fprintf(w, "After=%s\n", strings.Join(deps, " "))
+			if setup := oneshotUnits(target, procs, p.after); len(setup) > 0 {
+				fmt.Fprintf(w, "Requires=%s\n", strings.Join(setup, " "))
+			}
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "[Service]")
+		if p.dir != "" {
+			fmt.Fprintf(w, "WorkingDirectory=%s\n", systemdEscape(p.dir))
+		}
+		for _, k := range sortedKeys(p.env) {
+			fmt.Fprintf(w, "Environment=%s\n", systemdEscape(strconv.Quote(k+"="+p.env[k])))
+		}
+		for _, h := range slices.Concat(p.hooks.before, p.hooks.beforeEach) {
+			fmt.Fprintf(w, "ExecStartPre=%s\n", systemdHook(h))
+		}
+		fmt.Fprintf(w, "ExecStart=%s\n", systemdShell("exec "+p.command))
+		for _, h := range p.hooks.after {
+			fmt.Fprintf(w, "ExecStartPost=%s\n", systemdHook(h))
+		}
+		if p.oneshot {
+			fmt.Fprintln(w, "Type=oneshot")
+			fmt.Fprintln(w, "RemainAfterExit=yes")
+		} else {
+			fmt.Fprintln(w, "Restart=on-failure")
+		}
+	}
+	return nil
+}
+
+// systemdShell renders line as a command line running it with /bin/sh.
+// ExecStart also expands $VAR itself; $$ leaves that to the shell.
+func systemdShell(line string) string {
+	return "/bin/sh -c " + strings.ReplaceAll(systemdEscape(strconv.Quote(line)), "$", "$$")
+}
+
+// systemdHook renders h for an Exec line; the "-" prefix makes systemd
+// carry on when a hook that does not abort fails.
+func systemdHook(h hook) string {
+	line := systemdShell(shellLine(process{dir: h.dir, env: h.env, command: h.command}))
+	if !h.abort {
+		return "-" + line
+	}
+	return line
+}
+
+// systemdEscape keeps systemd from reading % in s as a specifier.
+func systemdEscape(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// oneshotUnits returns the unit names of the oneshot processes among ids, so
+// a failed setup step keeps its dependents from starting.
+func oneshotUnits(target string, procs []process, ids []string) []string {
+	var units []string
+	for _, p := range procs {
+		if p.oneshot && slices.Contains(ids, p.id) {
+			units = append(units, target+"-"+p.id+".service")
+		}
+	}
+	return units
+}
+
+// shellLine renders a process as a single POSIX shell command line that
+// changes into its directory and sets its environment.
+func shellLine(p process) string {
+	var b strings.Builder
+	if p.dir != "" {
+		fmt.Fprintf(&b, "cd %s && ", shellQuote(p.dir))
+	}
+	for _, k := range sortedKeys(p.env) {
+		fmt.Fprintf(&b, "%s=%s ", k, shellQuote(p.env[k]))
+	}
+	b.WriteString(p.command)
+	return b.String()
+}
+
+// shellQuote quotes s for POSIX shells unless it only contains safe characters.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
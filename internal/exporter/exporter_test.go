@@ -0,0 +1,338 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// shop has a service group followed by a group with a oneshot, and values
+// that each format has to escape.
+var shop = projects.Project{Groups: []projects.CommandGroup{
+	{Name: "DB", AbsolutePath: "/srv/shop", Commands: projects.NewCommands("postgres -D data")},
+	{Name: "api", AbsolutePath: "/srv/shop/api", Env: map[string]string{"PORT": "8080", "FMT": "50%"}, Commands: []projects.Command{
+		{Command: "go generate ./...", Kind: projects.KindOneshot},
+		{Command: `sh -c 'echo "$PORT" 100%'`},
+	}},
+}}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "procfile", want: `# Generated by vunat export from project "shop".
+# Processes are listed in start order; Procfile runners start them together.
+db: cd /srv/shop && postgres -D data
+api-1: cd /srv/shop/api && FMT=50% PORT=8080 go generate ./...
+api-2: cd /srv/shop/api && FMT=50% PORT=8080 sh -c 'echo "$PORT" 100%'
+`},
+		{format: "compose", want: `# vunat runs these commands on the host. Here each one runs in an image
+# guessed from its command, with its directory mounted at /app; change
+# image: where the guess lacks the tools it needs.
+services:
+  db:
+    image: "alpine:3"
+    working_dir: /app
+    volumes:
+      - "/srv/shop:/app"
+    command:
+      - "postgres"
+      - "-D"
+      - "data"
+  api-1:
+    image: "golang:1"
+    working_dir: /app
+    volumes:
+      - "/srv/shop/api:/app"
+    command:
+      - "go"
+      - "generate"
+      - "./..."
+    environment:
+      "FMT": "50%"
+      "PORT": "8080"
+    restart: "no"
+    depends_on:
+      db:
+        condition: service_started
+  api-2:
+    image: "alpine:3"
+    working_dir: /app
+    volumes:
+      - "/srv/shop/api:/app"
+    command:
+      - "sh"
+      - "-c"
+      - "echo \"$$PORT\" 100%"
+    environment:
+      "FMT": "50%"
+      "PORT": "8080"
+    depends_on:
+      api-1:
+        condition: service_completed_successfully
+`},
+		{format: "sh", want: `#!/bin/sh
+# Generated by vunat export from project "shop".
+# Runs oneshot commands to completion, starts every other process in the
+# background with its output prefixed by the group name, and stops them all
+# when the script is interrupted or a oneshot fails.
+set -e
+trap 'trap - INT TERM EXIT; kill 0' INT TERM EXIT
+
+prefix() { while IFS= read -r line; do printf '%s%s\n' "$1" "$line"; done; }
+
+echo '[DB] Starting in: /srv/shop'
+(cd /srv/shop && postgres -D data) 2>&1 | prefix '[DB] ' &
+echo '[DB] Started'
+
+echo '[api] Starting in: /srv/shop/api'
+( (cd /srv/shop/api && FMT=50% PORT=8080 go generate ./...) 2>&1 || kill 0 ) | prefix '[api] '
+(cd /srv/shop/api && FMT=50% PORT=8080 sh -c 'echo "$PORT" 100%') 2>&1 | prefix '[api] ' &
+echo '[api] Started'
+
+wait
+`},
+		{format: "systemd", want: `# Generated by vunat export from project "shop".
+# Save each unit below under ~/.config/systemd/user/ and run:
+#   systemctl --user daemon-reload && systemctl --user start vunat-shop.target
+
+# --- vunat-shop.target ---
+[Unit]
+Description=vunat project shop
+Wants=vunat-shop-db.service vunat-shop-api-1.service vunat-shop-api-2.service
+
+# --- vunat-shop-db.service ---
+[Unit]
+Description=postgres -D data (DB)
+PartOf=vunat-shop.target
+
+[Service]
+WorkingDirectory=/srv/shop
+ExecStart=/bin/sh -c "exec postgres -D data"
+Restart=on-failure
+
+# --- vunat-shop-api-1.service ---
+[Unit]
+Description=go generate ./... (api)
+PartOf=vunat-shop.target
+After=vunat-shop-db.service
+
+[Service]
+WorkingDirectory=/srv/shop/api
+Environment="FMT=50%%"
+Environment="PORT=8080"
+ExecStart=/bin/sh -c "exec go generate ./..."
+Type=oneshot
+RemainAfterExit=yes
+
+# --- vunat-shop-api-2.service ---
+[Unit]
+Description=sh -c 'echo "$PORT" 100%%' (api)
+PartOf=vunat-shop.target
+After=vunat-shop-api-1.service
+Requires=vunat-shop-api-1.service
+
+[Service]
+WorkingDirectory=/srv/shop/api
+Environment="FMT=50%%"
+Environment="PORT=8080"
+ExecStart=/bin/sh -c "exec sh -c 'echo \"$$PORT\" 100%%'"
+Restart=on-failure
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var b strings.Builder
+			if err := Export(&b, "shop", shop, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Export(%s) =\n%s\nwant\n%s", tt.format, b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		proj    projects.Project
+		format  string
+		wantErr string
+	}{
+		{name: "no commands", proj: projects.Project{Groups: []projects.CommandGroup{{Name: "web", Commands: projects.NewCommands("  ")}}}, format: "sh", wantErr: "no commands"},
+		{name: "unknown format", proj: shop, format: "nomad", wantErr: "unknown export format"},
+		{name: "malformed command", proj: projects.Project{Groups: []projects.CommandGroup{{Name: "web", Commands: projects.NewCommands("echo it's")}}}, format: "procfile", wantErr: "unterminated"},
+		{name: "hooks in procfile", proj: hooked, format: "procfile", wantErr: "has hooks"},
+		{name: "hooks in compose", proj: hooked, format: "compose", wantErr: "has hooks"},
+		{name: "malformed hook", proj: projects.Project{Hooks: projects.Hooks{Before: []string{"echo it's"}}, Groups: shop.Groups}, format: "sh", wantErr: "unterminated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			err := Export(&b, "shop", tt.proj, tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Export() error = %v, want one containing %q", err, tt.wantErr)
+			}
+			if b.Len() > 0 {
+				t.Errorf("Export() wrote %q before failing", b.String())
+			}
+		})
+	}
+}
+
+func TestImageFor(t *testing.T) {
+	tests := map[string]string{
+		"npm":                      "node:lts",
+		"./node_modules/.bin/vite": "alpine:3",
+		"/usr/local/go/bin/go":     "golang:1",
+		"python3":                  "python:3",
+		"make":                     "alpine:3",
+	}
+	for program, want := range tests {
+		if got := imageFor(program); got != want {
+			t.Errorf("imageFor(%q) = %q, want %q", program, got, want)
+		}
+	}
+}
+
+// hooked has project and group hooks, including those of a group without
+// commands, which run with the next group's.
+var hooked = projects.Project{
+	Hooks: projects.Hooks{Before: []string{"docker compose up -d"}, BeforeEach: []string{"date"}, AfterStop: []string{"docker compose down"}},
+	Groups: []projects.CommandGroup{
+		{Name: "db", AbsolutePath: "/srv/shop", Hooks: projects.Hooks{Before: []string{"pg_ctl start"}, AfterStop: []string{"pg_ctl stop"}}},
+		{Name: "api", AbsolutePath: "/srv/shop/api", Commands: projects.NewCommands("go run .", "go run ./worker"), Hooks: projects.Hooks{
+			After:       []string{"curl -fs localhost:8080"},
+			AfterStop:   []string{"rm -f api.pid"},
+			HookFailure: projects.HookContinue,
+		}},
+		{Name: "web", AbsolutePath: "/srv/shop/web", Commands: projects.NewCommands("npm run dev"), Hooks: projects.Hooks{Before: []string{"npm ci"}}},
+	},
+}
+
+func TestExportHooks(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "sh", want: `#!/bin/sh
+# Generated by vunat export from project "shop".
+# Runs oneshot commands to completion, starts every other process in the
+# background with its output prefixed by the group name, and stops them all
+# when the script is interrupted or a oneshot fails.
+set -e
+
+prefix() { while IFS= read -r line; do printf '%s%s\n' "$1" "$line"; done; }
+
+stop() {
+	trap - INT TERM EXIT
+	trap '' TERM
+	kill 0
+	wait || true
+	trap - TERM
+	( (cd /srv/shop/api && rm -f api.pid) 2>&1 || true ) | prefix '[api:afterStop] '
+	( (cd /srv/shop && pg_ctl stop) 2>&1 || true ) | prefix '[db:afterStop] '
+	( (cd /srv/shop && docker compose down) 2>&1 || true ) | prefix '[project:afterStop] '
+	exit "$1"
+}
+trap 'stop 130' INT
+trap 'stop 143' TERM
+trap 'stop $?' EXIT
+
+echo '[api] Starting in: /srv/shop/api'
+( (cd /srv/shop && docker compose up -d) 2>&1 || kill 0 ) | prefix '[project:before] '
+( (cd /srv/shop && pg_ctl start) 2>&1 || kill 0 ) | prefix '[db:before] '
+( (cd /srv/shop/api && date) 2>&1 || kill 0 ) | prefix '[project:beforeEach] '
+(cd /srv/shop/api && go run .) 2>&1 | prefix '[api] ' &
+( (cd /srv/shop/api && date) 2>&1 || kill 0 ) | prefix '[project:beforeEach] '
+(cd /srv/shop/api && go run ./worker) 2>&1 | prefix '[api] ' &
+echo '[api] Started'
+( (cd /srv/shop/api && curl -fs localhost:8080) 2>&1 || true ) | prefix '[api:after] '
+
+echo '[web] Starting in: /srv/shop/web'
+( (cd /srv/shop/web && npm ci) 2>&1 || kill 0 ) | prefix '[web:before] '
+( (cd /srv/shop/web && date) 2>&1 || kill 0 ) | prefix '[project:beforeEach] '
+(cd /srv/shop/web && npm run dev) 2>&1 | prefix '[web] ' &
+echo '[web] Started'
+
+wait
+`},
+		{format: "systemd", want: `# Generated by vunat export from project "shop".
+# Save each unit below under ~/.config/systemd/user/ and run:
+#   systemctl --user daemon-reload && systemctl --user start vunat-shop.target
+# Hooks run as ExecStartPre/ExecStartPost steps, again when a unit restarts.
+
+# --- vunat-shop.target ---
+[Unit]
+Description=vunat project shop
+Wants=vunat-shop.afterstop.service vunat-shop-api-1.service vunat-shop-api-2.service vunat-shop-web.service
+
+# --- vunat-shop.afterstop.service ---
+[Unit]
+Description=afterStop hooks of vunat project shop
+PartOf=vunat-shop.target
+Before=vunat-shop-api-1.service vunat-shop-api-2.service vunat-shop-web.service
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/true
+ExecStop=-/bin/sh -c "cd /srv/shop/api && rm -f api.pid"
+ExecStop=-/bin/sh -c "cd /srv/shop && pg_ctl stop"
+ExecStop=-/bin/sh -c "cd /srv/shop && docker compose down"
+
+# --- vunat-shop-api-1.service ---
+[Unit]
+Description=go run . (api)
+PartOf=vunat-shop.target
+
+[Service]
+WorkingDirectory=/srv/shop/api
+ExecStartPre=/bin/sh -c "cd /srv/shop && docker compose up -d"
+ExecStartPre=/bin/sh -c "cd /srv/shop && pg_ctl start"
+ExecStartPre=/bin/sh -c "cd /srv/shop/api && date"
+ExecStart=/bin/sh -c "exec go run ."
+Restart=on-failure
+
+# --- vunat-shop-api-2.service ---
+[Unit]
+Description=go run ./worker (api)
+PartOf=vunat-shop.target
+After=vunat-shop-api-1.service
+
+[Service]
+WorkingDirectory=/srv/shop/api
+ExecStartPre=/bin/sh -c "cd /srv/shop/api && date"
+ExecStart=/bin/sh -c "exec go run ./worker"
+ExecStartPost=-/bin/sh -c "cd /srv/shop/api && curl -fs localhost:8080"
+Restart=on-failure
+
+# --- vunat-shop-web.service ---
+[Unit]
+Description=npm run dev (web)
+PartOf=vunat-shop.target
+After=vunat-shop-api-1.service vunat-shop-api-2.service
+
+[Service]
+WorkingDirectory=/srv/shop/web
+ExecStartPre=/bin/sh -c "cd /srv/shop/web && npm ci"
+ExecStartPre=/bin/sh -c "cd /srv/shop/web && date"
+ExecStart=/bin/sh -c "exec npm run dev"
+Restart=on-failure
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var b strings.Builder
+			if err := Export(&b, "shop", hooked, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Export(%s) =\n%s\nwant\n%s", tt.format, b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// composeService holds the fields of a compose service that map onto a
+// command group.
+type composeService struct {
+	name      string
+	command   string
+	dir       string
+	env       map[string]string
+	dependsOn []string
+}
+
+// parseCompose converts every service of a docker-compose file into a command
+// group. Services with a command run it in their build context (or
+// working_dir when it is a relative path); services without one are started
+// through `docker compose up`. Groups are ordered so that depends_on targets
+// come first.
+func parseCompose(dir, path string) ([]projects.CommandGroup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a mapping at the top level")
+	}
+	rawServices, ok := root["services"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("no services defined")
+	}
+
+	services := make(map[string]composeService, len(rawServices))
+	for name, raw := range rawServices {
+		def, _ := raw.(map[string]any)
+		svc, err := composeServiceFrom(dir, path, name, def)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", name, err)
+		}
+		services[name] = svc
+	}
+
+	order, err := dependencyOrder(services)
+	if err != nil {
+		return nil, err
+	}
+	proj := make([]projects.CommandGroup, 0, len(order))
+	for _, name := range order {
+		svc := services[name]
+		proj = append(proj, projects.CommandGroup{
+			Name:         svc.name,
+			AbsolutePath: svc.dir,
+			Commands:     projects.NewCommands(svc.command),
+			Env:          svc.env,
+		})
+	}
+	return proj, nil
+}
+
+func composeServiceFrom(dir, path, name string, def map[string]any) (composeService, error) {
+	svc := composeService{name: name, dir: dir}
+
+	switch cmd := def["command"].(type) {
+	case nil:
+		svc.command = fmt.Sprintf("docker compose -f %s up %s", filepath.Base(path), name)
+	case string:
+		svc.command = cmd
+	case []any:
+		svc.command = projects.QuoteArgs(stringList(cmd))
+	default:
+		return svc, fmt.Errorf("unsupported command value")
+	}
+
+	if def["command"] != nil {
+		switch build := def["build"].(type) {
+		case string:
+			svc.dir = resolve(dir, build)
+		case map[string]any:
+			if ctx, ok := build["context"].(string); ok {
+				svc.dir = resolve(dir, ctx)
+			}
+		}
+		if wd, ok := def["working_dir"].(string); ok && wd != "" && !filepath.IsAbs(wd) {
+			svc.dir = resolve(dir, wd)
+		}
+	}
+
+	// A variable without a value ("KEY:" or "- KEY") takes its value from
+	// the environment vunat runs in, so it is left out rather than set
+	// empty.
+	switch env := def["environment"].(type) {
+	case map[string]any:
+		svc.env = make(map[string]string, len(env))
+		for k, v := range env {
+			if s, ok := v.(string); ok {
+				svc.env[k] = s
+			}
+		}
+	case []any:
+		svc.env = make(map[string]string, len(env))
+		for _, item := range stringList(env) {
+			if k, v, ok := strings.Cut(item, "="); ok {
+				svc.env[k] = v
+			}
+		}
+	}
+	if len(svc.env) == 0 {
+		svc.env = nil
+	}
+
+	switch deps := def["depends_on"].(type) {
+	case []any:
+		svc.dependsOn = stringList(deps)
+	case map[string]any:
+		for k := range deps {
+			svc.dependsOn = append(svc.dependsOn, k)
+		}
+		sort.Strings(svc.dependsOn)
+	}
+	return svc, nil
+}
+
+// dependencyOrder topologically sorts services so dependencies start first.
+// Services without ordering constraints between them are sorted by name to
+// keep the output stable.
+func dependencyOrder(services map[string]composeService) ([]string, error) {
+	names := make([]string, 0, len(services))
+	for n := range services {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = iota + 1
+		done
+	)
+	state := make(map[string]int, len(services))
+	order := make([]string, 0, len(services))
+	var visit func(name string, chain []string) error
+	visit = func(name string, chain []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("depends_on cycle: %s", strings.Join(append(chain, name), " -> "))
+		case done:
+			return nil
+		}
+		svc, ok := services[name]
+		if !ok {
+			return fmt.Errorf("service %q depends on undefined service %q", chain[len(chain)-1], name)
+		}
+		state[name] = visiting
+		for _, dep := range svc.dependsOn {
+			if err := visit(dep, append(chain, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		order = append(order, name)
+		return nil
+	}
+	for _, n := range names {
+		if err := visit(n, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func stringList(items []any) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func resolve(dir, p string) string {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	return filepath.Join(dir, p)
+}
@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// skipDirs are subdirectories never inspected by Detect.
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+	"bin":          true,
+}
+
+// Detect proposes command groups for root and its immediate subdirectories.
+// A directory with a Procfile or compose file is imported from it as-is;
+// otherwise go.mod, package.json and Cargo.toml each contribute a group and
+// a Makefile is only used when none of those are present. Errors in
+// individual files are skipped so a broken manifest doesn't block the rest.
+func Detect(root string) ([]projects.CommandGroup, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	dirs := []string{root}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") && !skipDirs[e.Name()] {
+			dirs = append(dirs, filepath.Join(root, e.Name()))
+		}
+	}
+
+	var proj []projects.CommandGroup
+	used := make(map[string]bool)
+	for _, dir := range dirs {
+		for _, g := range detectDir(dir) {
+			base := g.Name
+			for n := 2; used[g.Name]; n++ {
+				g.Name = fmt.Sprintf("%s-%d", base, n)
+			}
+			used[g.Name] = true
+			proj = append(proj, g)
+		}
+	}
+	return proj, nil
+}
+
+func detectDir(dir string) []projects.CommandGroup {
+	for _, src := range Sources[:2] { // procfile, compose
+		if path := Find(dir, src); path != "" {
+			if proj, err := src.Parse(dir, path); err == nil && len(proj) > 0 {
+				return proj
+			}
+		}
+	}
+
+	// Language groups are named after the directory, with a suffix per
+	// language when one directory hosts several (e.g. a Go API and a web UI).
+	type langGroup struct {
+		suffix string
+		cmds   []string
+	}
+	var langs []langGroup
+	if cmds := goCommands(dir); len(cmds) > 0 {
+		langs = append(langs, langGroup{"go", cmds})
+	}
+	if path := Find(dir, Sources[2]); path != "" {
+		if p, err := parsePackageJSON(dir, path); err == nil && len(p) > 0 {
+			langs = append(langs, langGroup{"web", projects.CommandLines(p[0].Commands)})
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Cargo.toml")); err == nil {
+		langs = append(langs, langGroup{"rust", []string{"cargo run"}})
+	}
+
+	if len(langs) == 0 {
+		if path := Find(dir, Sources[3]); path != "" {
+			if p, err := parseMakefile(dir, path); err == nil {
+				return p
+			}
+		}
+		return nil
+	}
+
+	proj := make([]projects.CommandGroup, 0, len(langs))
+	for _, l := range langs {
+		name := filepath.Base(dir)
+		if len(langs) > 1 {
+			name += "-" + l.suffix
+		}
+		proj = append(proj, projects.CommandGroup{Name: name, AbsolutePath: dir, Commands: projects.NewCommands(l.cmds...)})
+	}
+	return proj
+}
+
+// goCommands returns `go run` invocations for the main packages of the Go
+// module in dir: the module root itself and each directory under cmd/.
+func goCommands(dir string) []string {
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		return nil
+	}
+	var cmds []string
+	if isMainPackage(dir) {
+		cmds = append(cmds, "go run .")
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "cmd"))
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() && isMainPackage(filepath.Join(dir, "cmd", e.Name())) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		cmds = append(cmds, "go run ./cmd/"+n)
+	}
+	return cmds
+}
+
+// isMainPackage reports whether dir contains a non-test Go file declaring
+// package main.
+func isMainPackage(dir string) bool {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "package ") {
+				if strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "package")) == "main" {
+					return true
+				}
+				break
+			}
+		}
+	}
+	return false
+}
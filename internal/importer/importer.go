@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// Source is a process definition format that can be translated into a
+// vunat project.
+type Source struct {
+	// Name identifies the source, e.g. for `vunat import --from <name>`.
+	Name string
+	// Files lists the candidate file names in order of preference.
+	Files []string
+	// Parse converts the file at path into command groups. dir is the
+	// directory being imported and is used to resolve relative paths.
+	Parse func(dir, path string) ([]projects.CommandGroup, error)
+}
+
+// Sources lists the supported formats in detection order: the first source
+// with a matching file in the directory wins.
+var Sources = []Source{
+	{Name: "procfile", Files: []string{"Procfile"}, Parse: parseProcfile},
+	{Name: "compose", Files: []string{"compose.yaml", "compose.yml", "docker-compose.yml", "docker-compose.yaml"}, Parse: parseCompose},
+	{Name: "npm", Files: []string{"package.json"}, Parse: parsePackageJSON},
+	{Name: "make", Files: []string{"Makefile", "makefile", "GNUmakefile"}, Parse: parseMakefile},
+}
+
+// Result is a project generated from a process definition file.
+type Result struct {
+	// Source is the Name of the Source that produced the project.
+	Source string
+	// File is the absolute path of the file that was imported.
+	File string
+	// Project holds the generated command groups.
+	Project projects.Project
+}
+
+// Import inspects dir and converts the first detected process definition file
+// into a project. If from is non-empty only the Source with that name is
+// considered.
+func Import(dir, from string) (Result, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	if info, err := os.Stat(abs); err != nil {
+		return Result{}, err
+	} else if !info.IsDir() {
+		return Result{}, fmt.Errorf("%s is not a directory", abs)
+	}
+
+	names := make([]string, 0, len(Sources))
+	for _, src := range Sources {
+		names = append(names, src.Name)
+		if from != "" && src.Name != from {
+			continue
+		}
+		path := Find(abs, src)
+		if path == "" {
+			continue
+		}
+		proj, err := src.Parse(abs, path)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to import %s: %w", path, err)
+		}
+		if len(proj) == 0 {
+			return Result{}, fmt.Errorf("%s does not define any runnable commands", path)
+		}
+		return Result{Source: src.Name, File: path, Project: projects.Project{Groups: proj}}, nil
+	}
+
+	if from != "" {
+		for _, n := range names {
+			if n == from {
+				return Result{}, fmt.Errorf("no %s definition found in %s", from, abs)
+			}
+		}
+		return Result{}, fmt.Errorf("unknown import source %q (expected one of: %s)", from, strings.Join(names, ", "))
+	}
+	return Result{}, fmt.Errorf("no Procfile, compose file, package.json or Makefile found in %s", abs)
+}
+
+// Find returns the path of the first of src.Files present in dir, or "".
+func Find(dir string, src Source) string {
+	for _, name := range src.Files {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		from    string
+		// want lists the groups with dir relative to the imported one.
+		want    []projects.CommandGroup
+		wantErr string
+	}{
+		{
+			name:    "procfile",
+			file:    "Procfile",
+			content: "# processes\nweb: npm start -- --port $PORT\n\nworker: bin/worker: fast\n",
+			want: []projects.CommandGroup{
+				{Name: "web", Commands: projects.NewCommands("sh -c 'npm start -- --port $PORT'")},
+				{Name: "worker", Commands: projects.NewCommands("bin/worker: fast")},
+			},
+		},
+		{
+			name: "procfile shell lines",
+			file: "Procfile",
+			content: `web: PORT=$PORT bundle exec puma
+setup: bin/setup && bin/seed
+log: tail -f log/dev.log > /dev/null 2>&1
+say: echo it's "quoted"
+plain: bundle exec sidekiq -q "default queue"
+`,
+			want: []projects.CommandGroup{
+				{Name: "web", Commands: projects.NewCommands("sh -c 'PORT=$PORT bundle exec puma'")},
+				{Name: "setup", Commands: projects.NewCommands("sh -c 'bin/setup && bin/seed'")},
+				{Name: "log", Commands: projects.NewCommands("sh -c 'tail -f log/dev.log > /dev/null 2>&1'")},
+				{Name: "say", Commands: projects.NewCommands(`sh -c 'echo it'\''s "quoted"'`)},
+				{Name: "plain", Commands: projects.NewCommands(`bundle exec sidekiq -q "default queue"`)},
+			},
+		},
+		{
+			name:    "procfile without a command",
+			file:    "Procfile",
+			content: "web: npm start\nworker:\n",
+			wantErr: "line 2",
+		},
+		{
+			name: "compose",
+			file: "compose.yaml",
+			content: `services:
+  web:
+    build: ./web
+    command: npm run dev
+    depends_on: [api]
+    environment:
+      - API_URL=http://localhost:8080
+      - HOME
+  api:
+    build:
+      context: api
+    working_dir: cmd
+    command: ["go", "run", "."]
+    depends_on:
+      db:
+        condition: service_healthy
+    environment:
+      PORT: "8080"
+      TOKEN:
+      EMPTY: ""
+  db:
+    image: postgres
+  worker:
+    build: .
+    command: ["sh", "-c", "npm run worker -- --queue 'default'", ""]
+`,
+			want: []projects.CommandGroup{
+				{Name: "db", Commands: projects.NewCommands("docker compose -f compose.yaml up db")},
+				{Name: "api", AbsolutePath: "cmd", Commands: projects.NewCommands("go run ."), Env: map[string]string{"PORT": "8080", "EMPTY": ""}},
+				{Name: "web", AbsolutePath: "web", Commands: projects.NewCommands("npm run dev"), Env: map[string]string{"API_URL": "http://localhost:8080"}},
+				{Name: "worker", Commands: projects.NewCommands(`sh -c 'npm run worker -- --queue '\''default'\''' ''`)},
+			},
+		},
+		{
+			name:    "compose dependency cycle",
+			file:    "docker-compose.yml",
+			content: "services:\n  a:\n    depends_on: [b]\n  b:\n    depends_on: [a]\n",
+			wantErr: "cycle",
+		},
+		{
+			name:    "compose undefined dependency",
+			file:    "docker-compose.yml",
+			content: "services:\n  a:\n    depends_on: [b]\n",
+			wantErr: "undefined service",
+		},
+		{
+			name:    "compose without services",
+			file:    "compose.yml",
+			content: "version: '3'\n",
+			wantErr: "no services",
+		},
+		{
+			name:    "unknown source",
+			file:    "Procfile",
+			content: "web: x\n",
+			from:    "compose",
+			wantErr: "no compose definition",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			res, err := Import(dir, tt.from)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Import() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range tt.want {
+				tt.want[i].AbsolutePath = filepath.Join(dir, tt.want[i].AbsolutePath)
+			}
+			if !reflect.DeepEqual(res.Project.Groups, tt.want) {
+				t.Errorf("Import() groups =\n%+v\nwant\n%+v", res.Project.Groups, tt.want)
+			}
+		})
+	}
+}
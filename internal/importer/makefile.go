@@ -0,0 +1,69 @@
+package importer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// makeTargetRe matches a rule line ("target: deps") but not variable
+// assignments such as "X := y".
+var makeTargetRe = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_./-]*)\s*:([^=]|$)`)
+
+// runTargets are the Makefile targets that conventionally start the
+// application, in order of preference.
+var runTargets = []string{"dev", "run", "serve", "start", "watch"}
+
+// parseMakefile creates a single group running the preferred run target, or
+// the default (first) target if none of the conventional names exist.
+func parseMakefile(dir, path string) ([]projects.CommandGroup, error) {
+	targets, err := MakeTargets(path)
+	if err != nil || len(targets) == 0 {
+		return nil, err
+	}
+	target := targets[0]
+	for _, want := range runTargets {
+		if contains(targets, want) {
+			target = want
+			break
+		}
+	}
+	return []projects.CommandGroup{{
+		Name:         filepath.Base(dir),
+		AbsolutePath: dir,
+		Commands:     projects.NewCommands("make " + target),
+	}}, nil
+}
+
+// MakeTargets returns the explicit targets declared in a Makefile in the order
+// they appear. Special (.PHONY) and pattern (%) targets are skipped.
+func MakeTargets(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var targets []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := makeTargetRe.FindStringSubmatch(scanner.Text())
+		if m == nil || contains(targets, m[1]) {
+			continue
+		}
+		targets = append(targets, m[1])
+	}
+	return targets, scanner.Err()
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
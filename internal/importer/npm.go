@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// devScripts are the package.json scripts that conventionally start a
+// long-running development server, in order of preference.
+var devScripts = []string{"dev", "start", "serve", "watch"}
+
+// parsePackageJSON creates a single group running the preferred development
+// script with the package manager implied by the lockfile in dir.
+func parsePackageJSON(dir, path string) ([]projects.CommandGroup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("invalid package.json: %w", err)
+	}
+
+	for _, script := range devScripts {
+		if _, ok := pkg.Scripts[script]; ok {
+			return []projects.CommandGroup{{
+				Name:         filepath.Base(dir),
+				AbsolutePath: dir,
+				Commands:     projects.NewCommands(PackageManager(dir) + " run " + script),
+			}}, nil
+		}
+	}
+	return nil, nil
+}
+
+// PackageManager guesses the JavaScript package manager used in dir from its
+// lockfile, defaulting to npm.
+func PackageManager(dir string) string {
+	locks := []struct{ file, pm string }{
+		{"pnpm-lock.yaml", "pnpm"},
+		{"yarn.lock", "yarn"},
+		{"bun.lockb", "bun"},
+		{"bun.lock", "bun"},
+	}
+	for _, l := range locks {
+		if _, err := os.Stat(filepath.Join(dir, l.file)); err == nil {
+			return l.pm
+		}
+	}
+	return "npm"
+}
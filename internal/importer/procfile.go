@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// parseProcfile converts each "name: command" line of a Procfile into its own
+// command group running in dir. Procfile commands are shell lines, so one
+// using more than plain words is run through sh -c.
+
+func parseProcfile(dir, path string) ([]projects.CommandGroup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var proj []projects.CommandGroup
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, command, ok := strings.Cut(line, ":")
+		name, command = strings.TrimSpace(name), strings.TrimSpace(command)
+		if !ok || name == "" || command == "" {
+			return nil, fmt.Errorf("line %d: expected \"<process>: <command>\"", n)
+		}
+		proj = append(proj, projects.CommandGroup{
+			Name:         name,
+			AbsolutePath: dir,
+			Commands:     projects.NewCommands(shellCommand(command)),
+		})
+	}
+	return proj, scanner.Err()
+}
+
+// shellSyntax holds the characters that make a line more than words to a
+// shell: variables, operators, redirects, globs, comments and the like.
+const shellSyntax = "$&|;<>()`*?[]{}~#!"
+
+// shellCommand returns line as a command vunat can run without a shell,
+// wrapping it in sh -c if it uses shell syntax or starts with a variable
+// assignment.
+func shellCommand(line string) string {
+	args, err := projects.SplitArgs(line)
+	if err == nil && len(args) > 0 && !strings.ContainsAny(line, shellSyntax) && !strings.Contains(args[0], "=") {
+		return line
+	}
+	return projects.QuoteArgs([]string{"sh", "-c", line})
+}
@@ -0,0 +1,283 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a single significant (non-blank, non-comment) line of a YAML
+// document with its indentation measured in spaces.
+type yamlLine struct {
+	indent int
+	text   string
+	raw    string
+}
+
+// yamlParser decodes the small YAML subset used by docker-compose files:
+// block mappings and sequences, flow sequences/mappings, quoted and plain
+// scalars and literal/folded block scalars. Anchors, tags and multi-document
+// streams are not supported. Scalars are always returned as strings.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML decodes data into nested map[string]any / []any / string values.
+func parseYAML(data []byte) (any, error) {
+	p := &yamlParser{}
+	for _, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		if strings.Contains(raw, "\t") && strings.TrimLeft(raw, "\t") != raw {
+			return nil, fmt.Errorf("yaml: tabs are not allowed for indentation")
+		}
+		text := strings.TrimRight(stripComment(raw), " ")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		p.lines = append(p.lines, yamlLine{indent: len(text) - len(trimmed), text: trimmed, raw: raw})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	return p.parseNode(p.lines[0].indent)
+}
+
+func (p *yamlParser) parseNode(indent int) (any, error) {
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	line := p.lines[p.pos]
+	switch {
+	case isSeqItem(line.text):
+		return p.parseSeq(indent)
+	case splitKey(line.text) != "":
+		return p.parseMap(indent)
+	default:
+		p.pos++
+		return parseInline(line.text)
+	}
+}
+
+func (p *yamlParser) parseMap(indent int) (any, error) {
+	out := make(map[string]any)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("yaml: unexpected indentation: %q", strings.TrimSpace(line.raw))
+		}
+		key := splitKey(line.text)
+		if key == "" {
+			return nil, fmt.Errorf("yaml: expected mapping key: %q", strings.TrimSpace(line.raw))
+		}
+		rest := strings.TrimSpace(line.text[len(key)+1:])
+		name, err := unquote(strings.TrimSpace(key))
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+
+		var value any
+		switch {
+		case rest == "":
+			if p.pos < len(p.lines) {
+				next := p.lines[p.pos]
+				if next.indent > indent || (next.indent == indent && isSeqItem(next.text)) {
+					if value, err = p.parseNode(next.indent); err != nil {
+						return nil, err
+					}
+				}
+			}
+		case strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+			value = p.blockScalar(indent, rest[0] == '>')
+		default:
+			if value, err = parseInline(rest); err != nil {
+				return nil, err
+			}
+		}
+		out[name] = value
+	}
+	return out, nil
+}
+
+func (p *yamlParser) parseSeq(indent int) (any, error) {
+	out := make([]any, 0)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || !isSeqItem(line.text) {
+			break
+		}
+		rest := strings.TrimLeft(line.text[1:], " ")
+		if rest == "" {
+			p.pos++
+			var item any
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				var err error
+				if item, err = p.parseNode(p.lines[p.pos].indent); err != nil {
+					return nil, err
+				}
+			}
+			out = append(out, item)
+			continue
+		}
+		// Rewrite "- key: value" as a mapping line indented to the item content
+		// so nested keys of the same item line up with it.
+		p.lines[p.pos] = yamlLine{indent: indent + len(line.text) - len(rest), text: rest, raw: line.raw}
+		item, err := p.parseNode(p.lines[p.pos].indent)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// blockScalar collects the lines of a literal (|) or folded (>) scalar.
+func (p *yamlParser) blockScalar(indent int, folded bool) string {
+	var parts []string
+	for p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+		parts = append(parts, strings.TrimSpace(p.lines[p.pos].raw))
+		p.pos++
+	}
+	if folded {
+		return strings.Join(parts, " ")
+	}
+	return strings.Join(parts, "\n")
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey returns the key portion (without the colon) of a "key: value" line,
+// or "" if the line is not a mapping entry.
+func splitKey(text string) string {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return ""
+	}
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i == len(text)-1 || text[i+1] == ' '):
+			return text[:i]
+		}
+	}
+	return ""
+}
+
+// stripComment removes a trailing "# comment" that is not inside quotes.
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && opensQuote(s, i):
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// parseInline decodes a scalar or flow collection appearing on a single line.
+func parseInline(s string) (any, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "" || s == "~" || s == "null":
+		return nil, nil
+	case strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]"):
+		out := make([]any, 0)
+		for _, item := range splitFlow(s[1 : len(s)-1]) {
+			v, err := parseInline(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}"):
+		out := make(map[string]any)
+		for _, item := range splitFlow(s[1 : len(s)-1]) {
+			key := splitKey(item)
+			if key == "" {
+				return nil, fmt.Errorf("yaml: invalid flow mapping entry %q", item)
+			}
+			name, err := unquote(strings.TrimSpace(key))
+			if err != nil {
+				return nil, err
+			}
+			v, err := parseInline(item[len(key)+1:])
+			if err != nil {
+				return nil, err
+			}
+			out[name] = v
+		}
+		return out, nil
+	default:
+		return unquote(s)
+	}
+}
+
+// splitFlow splits the body of a flow collection on top-level commas.
+func splitFlow(s string) []string {
+	var out []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && opensQuote(s, i):
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			out = append(out, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		out = append(out, last)
+	}
+	return out
+}
+
+// opensQuote reports whether the quote character at s[i] starts a quoted
+// scalar rather than appearing inside a plain one (e.g. "don't").
+func opensQuote(s string, i int) bool {
+	return i == 0 || strings.IndexByte(" [{,:", s[i-1]) >= 0
+}
+
+func unquote(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return "", fmt.Errorf("yaml: invalid quoted string %s: %w", s, err)
+		}
+		return v, nil
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+	return s, nil
+}
@@ -0,0 +1,112 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tanuvnair/vunat-cli/internal/output"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    any
+		wantErr bool
+	}{
+		{name: "empty", in: "", want: nil},
+		{name: "comments only", in: "# nothing\n---\n", want: nil},
+		{name: "scalar", in: "hello", want: "hello"},
+		{
+			name: "nested mapping",
+			in:   "a:\n  b: c\n  d: e\nf: g\n",
+			want: map[string]any{"a": map[string]any{"b": "c", "d": "e"}, "f": "g"},
+		},
+		{
+			name: "sequence of scalars and mappings",
+			in:   "- one\n- two: 2\n  three: 3\n- - nested\n",
+			want: []any{"one", map[string]any{"two": "2", "three": "3"}, []any{"nested"}},
+		},
+		{
+			name: "sequence under a key at the same indent",
+			in:   "ports:\n- 80\n- 443\n",
+			want: map[string]any{"ports": []any{"80", "443"}},
+		},
+		{
+			name: "null values",
+			in:   "a:\nb: ~\nc: null\nd: ''\n",
+			want: map[string]any{"a": nil, "b": nil, "c": nil, "d": ""},
+		},
+		{
+			name: "quoted scalars",
+			in:   "a: \"x: #y\\n\"\nb: 'it''s'\nc: don't # comment\n",
+			want: map[string]any{"a": "x: #y\n", "b": "it's", "c": "don't"},
+		},
+		{
+			name: "flow collections",
+			in:   "a: [x, \"y, z\", [1, 2]]\nb: {k: v, q: 'a,b'}\nc: []\n",
+			want: map[string]any{
+				"a": []any{"x", "y, z", []any{"1", "2"}},
+				"b": map[string]any{"k": "v", "q": "a,b"},
+				"c": []any{},
+			},
+		},
+		{
+			name: "block scalars",
+			in:   "lit: |\n  one\n  two\nfold: >\n  one\n  two\nnext: x\n",
+			want: map[string]any{"lit": "one\ntwo", "fold": "one two", "next": "x"},
+		},
+		{name: "url value", in: "url: http://localhost:3000\n", want: map[string]any{"url": "http://localhost:3000"}},
+		{name: "crlf", in: "a: b\r\nc: d\r\n", want: map[string]any{"a": "b", "c": "d"}},
+		{name: "tab indentation", in: "a:\n\tb: c\n", wantErr: true},
+		{name: "bad indentation", in: "a: b\n  c: d\n", wantErr: true},
+		{name: "missing key", in: "a:\n  b: c\n  plain\n", wantErr: true},
+		{name: "bad flow mapping", in: "a: {b}\n", wantErr: true},
+		{name: "bad quoted string", in: "a: \"\\q\"\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML([]byte(tt.in))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseYAML() = %#v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseYAMLReadsOutput feeds the YAML written by `-o yaml` back through
+// parseYAML, which keeps every scalar as a string, to check that quoting
+// preserves structure and content.
+func TestParseYAMLReadsOutput(t *testing.T) {
+	values := []any{
+		map[string]any{"path": "/srv/shop", "url": "http://localhost:3000", "cmd": "go run ./..."},
+		map[string]any{"a": "note:", "b:": "x", "c": "key: value", "d": "a #b", "e": "#c"},
+		map[string]any{"cr": "one\rtwo", "lf": "one\ntwo", "tab": "a\tb", "quote": `say "hi"`, "apos": "don't", "bs": `C:\dir`},
+		map[string]any{"date": "2024-01-01", "port": "8080:80", "bool": "y", "no": "n", "null": "null", "empty": "", "pad": " a "},
+		[]any{"-x", "*a", "[1]", "{k: v}", "- y", []any{"nested", map[string]any{"k": "v:"}}},
+		map[string]any{"groups": []any{map[string]any{"name": "web", "commands": []any{"npm start"}, "env": map[string]any{"PORT": "3000"}}}},
+	}
+	for _, v := range values {
+		var b strings.Builder
+		if err := (&output.Printer{Format: output.YAML, Out: &b}).Print(v); err != nil {
+			t.Fatal(err)
+		}
+		got, err := parseYAML([]byte(b.String()))
+		if err != nil {
+			t.Fatalf("parseYAML(%q): %v", b.String(), err)
+		}
+		if !reflect.DeepEqual(got, v) {
+			t.Errorf("parseYAML(%q) = %#v, want %#v", b.String(), got, v)
+		}
+	}
+}
@@ -48,13 +48,9 @@ func (l *OSLauncher) Open(path string) error {
 	case "windows":
 		// 'start' is a cmd.exe builtin. The empty string argument after 'start'
 		// is the window title; it's required if the path may begin with a quote.
-		cmd = exec.Command("cmd", "/c", "start", "", path)
-	case "darwin":
-		cmd = exec.Command("open", path)
+		cmd = exec.Command(OpenerName(), "/c", "start", "", path)
 	default:
-		// Most desktop Linux environments provide xdg-open. If it's not present
-		// the command will fail; callers can detect and provide an alternative.
-		cmd = exec.Command("xdg-open", path)
+		cmd = exec.Command(OpenerName(), path)
 	}
 
 	if l.Wait {
@@ -69,3 +65,18 @@ func (l *OSLauncher) Open(path string) error {
 	}
 	return nil
 }
+
+// OpenerName returns the executable OSLauncher uses on the current platform:
+// "cmd" on Windows, "open" on macOS and "xdg-open" elsewhere.
+func OpenerName() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "cmd"
+	case "darwin":
+		return "open"
+	default:
+		// Most desktop Linux environments provide xdg-open. If it's not present
+		// the command will fail; callers can detect and provide an alternative.
+		return "xdg-open"
+	}
+}
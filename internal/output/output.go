@@ -0,0 +1,107 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format selects how command results are rendered.
+type Format string
+
+const (
+	// Default lets each command print its human-oriented output.
+	Default Format = ""
+	JSON    Format = "json"
+	YAML    Format = "yaml"
+	Table   Format = "table"
+	Names   Format = "names"
+)
+
+// Formats lists the values accepted by the global --output option.
+var Formats = []Format{JSON, YAML, Table, Names}
+
+// ParseFormat validates an --output value.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	names := make([]string, 0, len(Formats))
+	for _, f := range Formats {
+		names = append(names, string(f))
+	}
+	return Default, fmt.Errorf("unknown output format %q (expected one of: %s)", s, strings.Join(names, ", "))
+}
+
+// Tabular is implemented by results that can be rendered as a table or as a
+// plain list of names. JSON and YAML output is produced from the value itself
+// via encoding/json, so field order and names follow its json tags.
+type Tabular interface {
+	// Header returns the column titles.
+	Header() []string
+	// Rows returns one slice of cells per row, matching Header.
+	Rows() [][]string
+	// Names returns the identifiers printed by the "names" format.
+	Names() []string
+}
+
+// Printer renders command results in the format selected on the command line.
+// Commands receive a shared *Printer so the format can be set once by the CLI
+// before dispatch.
+type Printer struct {
+	Format Format
+	Out    io.Writer
+}
+
+// NewPrinter constructs a Printer with the default format writing to out.
+func NewPrinter(out io.Writer) *Printer {
+	return &Printer{Out: out}
+}
+
+// Structured reports whether a machine-readable format was requested, i.e.
+// whether the command should call Print instead of its own human output.
+func (p *Printer) Structured() bool {
+	return p != nil && p.Format != Default
+}
+
+// Print renders v in the selected format. Table and names output require v to
+// implement Tabular.
+func (p *Printer) Print(v any) error {
+	switch p.Format {
+	case JSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		_, err = fmt.Fprintf(p.Out, "%s\n", data)
+		return err
+	case YAML:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		return writeYAML(p.Out, data)
+	case Table, Names:
+		t, ok := v.(Tabular)
+		if !ok {
+			return fmt.Errorf("output format %q is not supported by this command", p.Format)
+		}
+		if p.Format == Names {
+			for _, n := range t.Names() {
+				fmt.Fprintln(p.Out, n)
+			}
+			return nil
+		}
+		w := tabwriter.NewWriter(p.Out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(t.Header(), "\t"))
+		for _, row := range t.Rows() {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("no output format selected")
+}
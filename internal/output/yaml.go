@@ -0,0 +1,202 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// writeYAML converts a JSON document to block-style YAML. Working from the
+// JSON token stream keeps object keys in the order encoding/json produced
+// them (struct field order, sorted map keys).
+func writeYAML(w io.Writer, data []byte) error {
+	node, err := decodeOrdered(json.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	var b strings.Builder
+	emitYAML(&b, node, 0, false)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// orderedMap is a JSON object with its keys in document order.
+type orderedMap struct {
+	keys   []string
+	values []any
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			m := &orderedMap{}
+			for dec.More() {
+				k, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				m.keys = append(m.keys, k.(string))
+				m.values = append(m.values, v)
+			}
+			_, err := dec.Token() // '}'
+			return m, err
+		case '[':
+			list := make([]any, 0)
+			for dec.More() {
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			_, err := dec.Token() // ']'
+			return list, err
+		}
+	}
+	return tok, nil
+}
+
+// emitYAML writes node at the given indentation. inSeq is set when the node
+// is the value of a "- " sequence entry, whose first line is already indented.
+func emitYAML(b *strings.Builder, node any, indent int, inSeq bool) {
+	pad := strings.Repeat("  ", indent)
+	switch n := node.(type) {
+	case *orderedMap:
+		if len(n.keys) == 0 {
+			b.WriteString("{}\n")
+			return
+		}
+		for i, k := range n.keys {
+			if i > 0 || !inSeq {
+				b.WriteString(pad)
+			}
+			b.WriteString(yamlScalar(k) + ":")
+			emitValue(b, n.values[i], indent+1)
+		}
+	case []any:
+		if len(n) == 0 {
+			b.WriteString("[]\n")
+			return
+		}
+		for i, item := range n {
+			if i > 0 || !inSeq {
+				b.WriteString(pad)
+			}
+			b.WriteString("- ")
+			if isCollection(item) {
+				emitYAML(b, item, indent+1, true)
+			} else {
+				b.WriteString(yamlScalar(item) + "\n")
+			}
+		}
+	default:
+		b.WriteString(yamlScalar(n) + "\n")
+	}
+}
+
+// emitValue writes the value of a mapping entry after its "key:".
+func emitValue(b *strings.Builder, v any, indent int) {
+	switch n := v.(type) {
+	case *orderedMap:
+		if len(n.keys) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		emitYAML(b, n, indent, false)
+	case []any:
+		if len(n) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		// Sequences under a key are indented at the key's level, as is common
+		// in hand-written YAML.
+		emitYAML(b, n, indent-1, false)
+	default:
+		b.WriteString(" " + yamlScalar(n) + "\n")
+	}
+}
+
+func isCollection(v any) bool {
+	switch n := v.(type) {
+	case *orderedMap:
+		return len(n.keys) > 0
+	case []any:
+		return len(n) > 0
+	}
+	return false
+}
+
+// yamlScalar renders a JSON scalar, quoting strings that YAML would otherwise
+// read as a different type or that contain special characters.
+func yamlScalar(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(s)
+	case json.Number:
+		return s.String()
+	case string:
+		if needsQuote(s) {
+			return strconv.Quote(s)
+		}
+		return s
+	case *orderedMap:
+		return "{}"
+	case []any:
+		return "[]"
+	}
+	return fmt.Sprint(v)
+}
+
+// needsQuote reports whether s has to be double-quoted to read back as the
+// same string. Besides indicators and special characters that covers plain
+// scalars YAML 1.1 resolves to another type: booleans such as y and off, and
+// anything starting like a number, which includes dates (2024-01-01) and
+// sexagesimal integers (8080:80).
+func needsQuote(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "y", "n", "on", "off", "null", "~", ".inf", "+.inf", ".nan":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	if isDigit(s[0]) || len(s) > 1 && strings.IndexByte("+.", s[0]) >= 0 && isDigit(s[1]) {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.Contains(s, "\\") {
+		return true
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
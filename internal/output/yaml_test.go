@@ -0,0 +1,40 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain strings", in: `{"path": "/srv/shop", "cmd": "npm run dev", "url": "http://localhost:3000"}`, want: "path: /srv/shop\ncmd: npm run dev\nurl: http://localhost:3000\n"},
+		{name: "trailing colon", in: `{"a": "note:", "b:": "x"}`, want: "a: \"note:\"\n\"b:\": x\n"},
+		{name: "colon then space", in: `{"a": "key: value"}`, want: "a: \"key: value\"\n"},
+		{name: "carriage return", in: `{"a": "one\rtwo"}`, want: "a: \"one\\rtwo\"\n"},
+		{name: "newline and tab", in: `{"a": "one\ntwo\tthree"}`, want: "a: \"one\\ntwo\\tthree\"\n"},
+		{name: "date", in: `{"a": "2024-01-01", "b": "2024-01-01T10:00:00Z"}`, want: "a: \"2024-01-01\"\nb: \"2024-01-01T10:00:00Z\"\n"},
+		{name: "sexagesimal", in: `["8080:80", "1:20"]`, want: "- \"8080:80\"\n- \"1:20\"\n"},
+		{name: "numbers", in: `["3000", "0x1f", "1_000", "+1", ".5", "1e3", 42]`, want: "- \"3000\"\n- \"0x1f\"\n- \"1_000\"\n- \"+1\"\n- \".5\"\n- \"1e3\"\n- 42\n"},
+		{name: "yaml 1.1 booleans", in: `["y", "N", "yes", "Off", "on", "TRUE", true]`, want: "- \"y\"\n- \"N\"\n- \"yes\"\n- \"Off\"\n- \"on\"\n- \"TRUE\"\n- true\n"},
+		{name: "null-like", in: `["null", "~", "", ".inf", ".NaN", null]`, want: "- \"null\"\n- \"~\"\n- \"\"\n- \".inf\"\n- \".NaN\"\n- null\n"},
+		{name: "indicators", in: `["-x", "*a", "&a", "!tag", "#c", "@x", "a #b", "' q"]`, want: "- \"-x\"\n- \"*a\"\n- \"&a\"\n- \"!tag\"\n- \"#c\"\n- \"@x\"\n- \"a #b\"\n- \"' q\"\n"},
+		{name: "not numbers", in: `["y2k", ".env", "go run ./...", "a-1", "don't"]`, want: "- y2k\n- .env\n- go run ./...\n- a-1\n- don't\n"},
+		{name: "padding and backslash", in: `[" a", "b ", "C:\\dir"]`, want: "- \" a\"\n- \"b \"\n- \"C:\\\\dir\"\n"},
+		{name: "nested", in: `{"groups": [{"name": "web", "commands": ["npm start"]}], "env": {}, "tags": []}`, want: "groups:\n- name: web\n  commands:\n  - npm start\nenv: {}\ntags: []\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := writeYAML(&b, []byte(tt.in)); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("writeYAML(%s) =\n%s\nwant\n%s", tt.in, b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Package plugin discovers external vunat commands: executables named
+// vunat-<name> in the plugin directory (~/.vunat/plugins) or on PATH.
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix is the file name prefix that marks an executable as a vunat plugin.
+const Prefix = "vunat-"
+
+// Plugin is an external command, invoked as `vunat <Name>`.
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// Dir returns the plugin directory that sits next to the config file at
+// configPath.
+func Dir(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "plugins")
+}
+
+// SearchPath returns the directories searched for plugins, in priority
+// order: the plugin directory, then every entry of $PATH.
+func SearchPath(pluginDir string) []string {
+	return append([]string{pluginDir}, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// Find returns the plugin called name from the first directory in dirs that
+// provides it.
+func Find(name string, dirs []string) (Plugin, bool) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return Plugin{}, false
+	}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		for _, file := range fileNames(Prefix + name) {
+			path := filepath.Join(dir, file)
+			if isExecutable(path) {
+				return Plugin{Name: name, Path: path}, true
+			}
+		}
+	}
+	return Plugin{}, false
+}
+
+// Discover lists the plugins found in dirs, sorted by name. When several
+// directories provide the same plugin, the first one wins, matching Find.
+func Discover(dirs []string) []Plugin {
+	seen := make(map[string]bool)
+	var out []Plugin
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := pluginName(e.Name())
+			if !ok || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			out = append(out, Plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// pluginName extracts the command name from a plugin file name, dropping the
+// executable extension on Windows.
+func pluginName(file string) (string, bool) {
+	if !strings.HasPrefix(file, Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, Prefix)
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !isWindowsExecExt(ext) {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name, name != ""
+}
+
+// fileNames returns the file names that can provide the executable base.
+func fileNames(base string) []string {
+	if runtime.GOOS != "windows" {
+		return []string{base}
+	}
+	var out []string
+	for _, ext := range windowsExecExts() {
+		out = append(out, base+ext)
+	}
+	return out
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return isWindowsExecExt(filepath.Ext(path))
+	}
+	return info.Mode()&0o111 != 0
+}
+
+func windowsExecExts() []string {
+	exts := strings.Split(strings.ToLower(os.Getenv("PATHEXT")), ";")
+	if os.Getenv("PATHEXT") == "" {
+		exts = []string{".com", ".exe", ".bat", ".cmd"}
+	}
+	return exts
+}
+
+func isWindowsExecExt(ext string) bool {
+	ext = strings.ToLower(ext)
+	for _, e := range windowsExecExts() {
+		if e != "" && e == ext {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+// Package procstat samples the CPU and memory use of process groups, so a
+// service is measured together with the processes it spawned. It reads
+// /proc on Linux and runs ps(1) elsewhere; where neither works, no usage is
+// reported.
+package procstat
+
+import "time"
+
+// Usage is the resource use of a process group.
+type Usage struct {
+	// CPU is the share of one core used since the previous sample, in
+	// percent (it can exceed 100 for multi-threaded processes).
+	CPU float64
+	// Memory is the resident memory in bytes.
+	Memory uint64
+}
+
+// Sampler measures process groups. CPU use is computed between successive
+// calls to Sample, so the first sample of a group reports no CPU use where
+// only cumulative CPU time is available.
+type Sampler struct {
+	last     time.Time
+	cpuTimes map[int]time.Duration
+}
+
+// Sample returns the usage of each process group in pgids that still has
+// processes. On Unix the group ID of a service is its PID.
+func (s *Sampler) Sample(pgids []int) map[int]Usage {
+	want := make(map[int]bool, len(pgids))
+	for _, id := range pgids {
+		want[id] = true
+	}
+	now := time.Now()
+	out := sample(want, s, now)
+	s.last = now
+	return out
+}
+
+// cpuPercent turns the cumulative CPU time of group into a percentage of
+// the time since the previous sample.
+func (s *Sampler) cpuPercent(group int, total time.Duration, now time.Time) float64 {
+	if s.cpuTimes == nil {
+		s.cpuTimes = make(map[int]time.Duration)
+	}
+	prev, ok := s.cpuTimes[group]
+	s.cpuTimes[group] = total
+	elapsed := now.Sub(s.last)
+	if !ok || s.last.IsZero() || elapsed <= 0 || total < prev {
+		return 0
+	}
+	return float64(total-prev) / float64(elapsed) * 100
+}
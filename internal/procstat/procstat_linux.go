@@ -0,0 +1,57 @@
+package procstat
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of the CPU times in /proc/<pid>/stat; it
+// is 100 on every Linux platform Go supports.
+const clockTicks = 100
+
+// sample sums the CPU time and resident memory of the processes in /proc
+// whose process group is wanted.
+func sample(want map[int]bool, s *Sampler, now time.Time) map[int]Usage {
+	stats, _ := filepath.Glob("/proc/[0-9]*/stat")
+	cpu := make(map[int]time.Duration)
+	out := make(map[int]Usage)
+	pageSize := uint64(os.Getpagesize())
+	for _, path := range stats {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		// The command name is in parentheses and may contain spaces, so
+		// the fields are counted from the last ')'.
+		i := bytes.LastIndexByte(data, ')')
+		if i < 0 {
+			continue
+		}
+		fields := bytes.Fields(data[i+1:])
+		// fields[0] is the state (field 3 of stat(5)); the process group
+		// is field 5, utime and stime are 14 and 15 and rss is 24.
+		if len(fields) < 22 {
+			continue
+		}
+		pgrp, _ := strconv.Atoi(string(fields[2]))
+		if !want[pgrp] {
+			continue
+		}
+		utime, _ := strconv.ParseUint(string(fields[11]), 10, 64)
+		stime, _ := strconv.ParseUint(string(fields[12]), 10, 64)
+		rss, _ := strconv.ParseUint(string(fields[21]), 10, 64)
+		cpu[pgrp] += time.Duration(utime+stime) * time.Second / clockTicks
+		u := out[pgrp]
+		u.Memory += rss * pageSize
+		out[pgrp] = u
+	}
+	for group, total := range cpu {
+		u := out[group]
+		u.CPU = s.cpuPercent(group, total, now)
+		out[group] = u
+	}
+	return out
+}
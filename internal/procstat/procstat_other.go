@@ -0,0 +1,41 @@
+//go:build !linux
+
+package procstat
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sample sums the %cpu and rss columns of ps(1) for the processes whose
+// process group is wanted. ps reports CPU use averaged over a recent
+// window rather than since the previous sample.
+func sample(want map[int]bool, s *Sampler, now time.Time) map[int]Usage {
+	data, err := exec.Command("ps", "-A", "-o", "pgid=,%cpu=,rss=").Output()
+	if err != nil {
+		return nil
+	}
+	out := make(map[int]Usage)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		pgid, _ := strconv.Atoi(fields[0])
+		if !want[pgid] {
+			continue
+		}
+		cpu, _ := strconv.ParseFloat(strings.ReplaceAll(fields[1], ",", "."), 64)
+		rss, _ := strconv.ParseUint(fields[2], 10, 64)
+		u := out[pgid]
+		u.CPU += cpu
+		u.Memory += rss * 1024
+		out[pgid] = u
+	}
+	return out
+}
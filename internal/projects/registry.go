@@ -1,25 +1,340 @@
 package projects
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
+	"github.com/tanuvnair/vunat-cli/internal/suggest"
 )
 
 type CommandGroup struct {
-	Name         string   `json:"name"`
-	AbsolutePath string   `json:"absolutePath"`
-	Commands     []string `json:"commands"`
+	Name         string            `json:"name"`
+	AbsolutePath string            `json:"absolutePath"`
+	Commands     []Command         `json:"commands"`
+	Env          map[string]string `json:"env,omitempty"`
+	Ports        []int             `json:"ports,omitempty"`
+	Hooks
+	Policy
+}
+
+// Failure policies, applied when a service exits with an error.
+const (
+	// FailureStopAll stops the whole project. It is the default.
+	FailureStopAll = "stop-all"
+	// FailureStopGroup stops the other services of the same group.
+	FailureStopGroup = "stop-group"
+	// FailureContinue keeps everything else running.
+	FailureContinue = "continue"
+)
+
+// Exit policies, applied when a service exits successfully.
+const (
+	// ExitIgnore keeps everything else running. It is the default.
+	ExitIgnore = "ignore"
+	// ExitStopAll stops the whole project.
+	ExitStopAll = "stop-all"
+)
+
+// Policy decides what happens to the rest of a project when one of its
+// services exits: OnFailure (FailureStopAll, FailureStopGroup or
+// FailureContinue) after an error, OnExit (ExitIgnore or ExitStopAll) after
+// a clean exit. Group settings override the project's.
+type Policy struct {
+	OnFailure string `json:"onFailure,omitempty"`
+	OnExit    string `json:"onExit,omitempty"`
+}
+
+// Command kinds.
+const (
+	// KindService is a long-running process. It is the default.
+	KindService = "service"
+	// KindOneshot is a setup step that must exit 0 before the group's
+	// services and the following groups are started.
+	KindOneshot = "oneshot"
+)
+
+// Command is one process of a command group. In the config file a service
+// may be written as just its command string.
+type Command struct {
+	Command string `json:"command"`
+	// Kind is KindService (default) or KindOneshot.
+	Kind string `json:"kind,omitempty"`
+	// Watch restarts the service when files change; services only.
+	Watch *Watch `json:"watch,omitempty"`
+	// TTY runs the command under a pseudo-terminal (Linux and macOS), so
+	// tools that check for a terminal keep their colors and progress
+	// output. Its stdout and stderr arrive as one stream.
+	TTY bool `json:"tty,omitempty"`
+	// Stdin makes the service read what is typed into vunat (or piped to
+	// it); at most one command of a project may set it.
+	Stdin bool `json:"stdin,omitempty"`
+}
+
+// Watch selects the files whose changes restart a service. Paths are files
+// or directories (watched recursively) relative to the group's
+// absolutePath, defaulting to the whole directory. Include and Exclude are
+// glob patterns: "**" matches any number of directories and a pattern
+// without "/" matches file names anywhere, e.g. "*.go" or "web/**/*.ts".
+// Debounce is a Go duration (default 300ms) that a burst of changes must
+// settle for before the restart.
+type Watch struct {
+	Paths    []string `json:"paths,omitempty"`
+	Include  []string `json:"include,omitempty"`
+	Exclude  []string `json:"exclude,omitempty"`
+	Debounce string   `json:"debounce,omitempty"`
+}
+
+// NewCommands returns service commands for the given command lines.
+func NewCommands(lines ...string) []Command {
+	cmds := make([]Command, 0, len(lines))
+	for _, l := range lines {
+		cmds = append(cmds, Command{Command: l})
+	}
+	return cmds
+}
+
+// CommandLines returns the command strings of cmds.
+func CommandLines(cmds []Command) []string {
+	lines := make([]string, 0, len(cmds))
+	for _, c := range cmds {
+		lines = append(lines, c.Command)
+	}
+	return lines
+}
+
+// SplitArgs splits a command line into arguments on whitespace, honouring
+// quotes and backslashes the way a POSIX shell does for simple words:
+// single quotes keep everything up to the next one, double quotes keep
+// everything but a backslash before ", \, $ or `, and elsewhere a backslash
+// keeps the next character. No other shell syntax is interpreted. An
+// unterminated quote or a trailing backslash is an error.
+func SplitArgs(s string) ([]string, error) {
+	var out []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, c := range s {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", c) {
+				cur.WriteRune('\\')
+			}
+			cur.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				cur.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inWord = c, true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				out = append(out, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(c)
+			inWord = true
+		}
+	}
+	switch {
+	case quote != 0:
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	case escaped:
+		return nil, fmt.Errorf("trailing backslash in %q", s)
+	}
+	if inWord {
+		out = append(out, cur.String())
+	}
+	return out, nil
+}
+
+// QuoteArgs joins args into a command line that SplitArgs splits back into
+// args. Arguments made of nothing but letters, digits and -_./:=@%+, are
+// left as they are; any other is put in single quotes.
+func QuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a != "" && strings.Trim(a, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@%+,") == "" {
+			quoted[i] = a
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Oneshot reports whether c is a setup step rather than a service.
+func (c Command) Oneshot() bool { return c.Kind == KindOneshot }
+
+// commandFields has the fields of Command without its JSON methods.
+type commandFields Command
+
+func (c Command) MarshalJSON() ([]byte, error) {
+	if c == (Command{Command: c.Command}) {
+		return json.Marshal(c.Command)
+	}
+	return json.Marshal(commandFields(c))
+}
+
+func (c *Command) UnmarshalJSON(data []byte) error {
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, `"`) {
+		*c = Command{}
+		return json.Unmarshal(data, &c.Command)
+	}
+	return json.Unmarshal(data, (*commandFields)(c))
+}
+
+// Hook failure policies.
+const (
+	// HookAbort stops the project when a hook fails. It is the default.
+	HookAbort = "abort"
+	// HookContinue reports a failed hook and carries on.
+	HookContinue = "continue"
+)
+
+// Hooks are commands run to completion around the long-running processes of
+// a project or group, one after another in the order listed:
+//   - Before runs before the first process is started.
+//   - BeforeEach runs before each process is started.
+//   - After runs once all processes have been started.
+//   - AfterStop runs once the processes have stopped, however they stopped.
+//
+// HookTimeout bounds each hook command (a Go duration such as "2m"; default
+// 10m) and HookFailure is HookAbort or HookContinue. Group settings override
+// the project's.
+type Hooks struct {
+	Before      []string `json:"before,omitempty"`
+	BeforeEach  []string `json:"beforeEach,omitempty"`
+	After       []string `json:"after,omitempty"`
+	AfterStop   []string `json:"afterStop,omitempty"`
+	HookTimeout string   `json:"hookTimeout,omitempty"`
+	HookFailure string   `json:"hookFailure,omitempty"`
+}
+
+// Project is the set of command groups started together by `vunat start`,
+// plus one-off tasks run with `vunat run`. In the config file a project with
+// nothing but groups may be written as a bare array of groups; Marshal keeps
+// that form so existing files are not rewritten.
+type Project struct {
+	Groups []CommandGroup  `json:"groups"`
+	Tasks  map[string]Task `json:"tasks,omitempty"`
+	Hooks
+	Policy
+}
+
+// Task is a one-off command such as a migration or code generator. It runs
+// in the directory and environment of Group (default: the project's first
+// group) unless AbsolutePath is set; Env adds to or overrides the group's
+// environment. In the config file a task may also be written as just its
+// command string.
+type Task struct {
+	Command      string            `json:"command"`
+	Group        string            `json:"group,omitempty"`
+	AbsolutePath string            `json:"absolutePath,omitempty"`
+	Env          map[string]string `json:"env,omitempty"`
+	Description  string            `json:"description,omitempty"`
+}
+
+// projectFields has the fields of Project without its JSON methods.
+type projectFields Project
+
+func (p Project) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(projectFields(p))
+	if err != nil {
+		return nil, err
+	}
+	// Keep the bare array form when the project has no other settings.
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if len(fields) == 1 {
+		groups := p.Groups
+		if groups == nil {
+			groups = []CommandGroup{}
+		}
+		return json.Marshal(groups)
+	}
+	return data, nil
+}
+
+func (p *Project) UnmarshalJSON(data []byte) error {
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		*p = Project{}
+		return json.Unmarshal(data, &p.Groups)
+	}
+	return json.Unmarshal(data, (*projectFields)(p))
 }
 
-type Project []CommandGroup
+// taskFields has the fields of Task without its JSON methods.
+type taskFields Task
+
+func (t Task) MarshalJSON() ([]byte, error) {
+	if t.Group == "" && t.AbsolutePath == "" && len(t.Env) == 0 && t.Description == "" {
+		return json.Marshal(t.Command)
+	}
+	return json.Marshal(taskFields(t))
+}
+
+func (t *Task) UnmarshalJSON(data []byte) error {
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, `"`) {
+		*t = Task{}
+		return json.Unmarshal(data, &t.Command)
+	}
+	return json.Unmarshal(data, (*taskFields)(t))
+}
+
+// Group returns the command group called name.
+func (p Project) Group(name string) (CommandGroup, bool) {
+	for _, g := range p.Groups {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return CommandGroup{}, false
+}
+
+// TaskNames returns the names of the project's tasks, sorted.
+func (p Project) TaskNames() []string {
+	names := make([]string, 0, len(p.Tasks))
+	for name := range p.Tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
 
 type Config struct {
 	Projects map[string]Project `json:"projects"`
+	// Aliases maps user-defined command names to a command line, e.g.
+	// "up": "start gradepoint".
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// extra keeps the top-level keys vunat does not know (such as "$schema"
+	// or settings of a newer version) so that Marshal writes them back.
+	extra map[string]json.RawMessage
 }
 
 var registry map[string]Project
+var aliases map[string]string
 
 func init() {
 	registry = make(map[string]Project)
@@ -56,27 +371,117 @@ func loadConfig() error {
 
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse config file: %w", err)
+		return &exitcode.ConfigError{Err: fmt.Errorf("failed to parse config file: %w", err)}
 	}
 
 	registry = config.Projects
+	aliases = config.Aliases
 	return nil
 }
 
+// Parse decodes raw config file contents into a Config. A missing "projects"
+// object yields an empty, non-nil map so callers can add entries directly.
+func Parse(data []byte) (Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Config{}, &exitcode.ConfigError{Err: fmt.Errorf("failed to parse config file: %w", err)}
+	}
+	if config.Projects == nil {
+		config.Projects = make(map[string]Project)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return Config{}, &exitcode.ConfigError{Err: fmt.Errorf("failed to parse config file: %w", err)}
+	}
+	delete(fields, "projects")
+	delete(fields, "aliases")
+	if len(fields) > 0 {
+		config.extra = fields
+	}
+	return config, nil
+}
+
+// Marshal encodes the config as indented JSON with a trailing newline, matching
+// the layout of the file created by config.FSManager.Ensure. Unknown keys read
+// by Parse follow the known ones, sorted.
+func (c Config) Marshal() ([]byte, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if len(c.extra) > 0 {
+		keys := make([]string, 0, len(c.extra))
+		for k := range c.extra {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var b bytes.Buffer
+		b.Write(data[:len(data)-1])
+		for _, k := range keys {
+			name, _ := json.Marshal(k)
+			b.WriteByte(',')
+			b.Write(name)
+			b.WriteByte(':')
+			b.Write(c.extra[k])
+		}
+		b.WriteByte('}')
+		data = b.Bytes()
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// Get returns the project registered under name. See Resolve for how name is
+// matched.
 func Get(name string) (Project, error) {
+	resolved, err := Resolve(name)
+	if err != nil {
+		return Project{}, err
+	}
+	return registry[resolved], nil
+}
+
+// Resolve returns the registered project name matching name: either an exact
+// match or the only project whose name starts with name. Unknown names are
+// reported with suggestions of similar project names.
+func Resolve(name string) (string, error) {
 	// Reload config in case it was updated
 	if err := loadConfig(); err != nil {
-		return Project{}, err
+		return "", err
 	}
 
-	project, ok := registry[name]
-	if !ok {
-		return Project{}, fmt.Errorf("unknown project: %s", name)
+	return Config{Projects: registry}.Resolve(name)
+}
+
+// Resolve is the package-level Resolve for the projects of c.
+func (c Config) Resolve(name string) (string, error) {
+	if _, ok := c.Projects[name]; ok {
+		return name, nil
+	}
+	names := make([]string, 0, len(c.Projects))
+	for n := range c.Projects {
+		names = append(names, n)
+	}
+	switch matches := suggest.Prefix(name, names); {
+	case name != "" && len(matches) == 1:
+		return matches[0], nil
+	case name != "" && len(matches) > 1:
+		return "", fmt.Errorf("ambiguous project %q: matches %s", name, strings.Join(matches, ", "))
 	}
-	return project, nil
+	return "", fmt.Errorf("unknown project %q%s", name, suggest.Hint(suggest.Similar(name, names)))
 }
 
 func GetAll() map[string]Project {
 	loadConfig() // Reload to get latest
 	return registry
 }
+
+// Aliases returns the user-defined command aliases from the config file.
+func Aliases() map[string]string {
+	loadConfig() // Reload to get latest
+	return aliases
+}
@@ -0,0 +1,142 @@
+package projects
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{in: "", want: nil},
+		{in: " \t\n", want: nil},
+		{in: "go run ./cmd/api", want: []string{"go", "run", "./cmd/api"}},
+		{in: "  npm   run\tdev  ", want: []string{"npm", "run", "dev"}},
+		{in: `sh -c 'echo hi; exit 3'`, want: []string{"sh", "-c", "echo hi; exit 3"}},
+		{in: `echo "a b" c`, want: []string{"echo", "a b", "c"}},
+		{in: `echo ''`, want: []string{"echo", ""}},
+		{in: `echo ""`, want: []string{"echo", ""}},
+		{in: `x'y'"z"`, want: []string{"xyz"}},
+		{in: `a\ b c`, want: []string{"a b", "c"}},
+		{in: `a\\b`, want: []string{`a\b`}},
+		{in: `\'quoted\'`, want: []string{"'quoted'"}},
+		{in: `'a\b'`, want: []string{`a\b`}},
+		{in: `"a\b"`, want: []string{`a\b`}},
+		{in: `"say \"hi\" \$HOME \\ \` + "`" + `"`, want: []string{"say \"hi\" $HOME \\ `"}},
+		{in: `"it's"`, want: []string{"it's"}},
+		{in: `'say "hi"'`, want: []string{`say "hi"`}},
+		{in: "echo it's", wantErr: true},
+		{in: `echo "open`, wantErr: true},
+		{in: `echo 'open`, wantErr: true},
+		{in: `echo "a\"`, wantErr: true},
+		{in: `trailing\`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := SplitArgs(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("SplitArgs(%q) = %q, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SplitArgs(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: nil, want: ""},
+		{args: []string{"go", "run", "./cmd/api", "--addr=:8080"}, want: "go run ./cmd/api --addr=:8080"},
+		{args: []string{"sh", "-c", "npm run dev"}, want: "sh -c 'npm run dev'"},
+		{args: []string{"echo", "it's", ""}, want: `echo 'it'\''s' ''`},
+		{args: []string{`a"b`, `c\d`, "$HOME", "x\ny"}, want: "'a\"b' 'c\\d' '$HOME' 'x\ny'"},
+	}
+	for _, tt := range tests {
+		got := QuoteArgs(tt.args)
+		if got != tt.want {
+			t.Errorf("QuoteArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+		back, err := SplitArgs(got)
+		if err != nil || !reflect.DeepEqual(back, tt.args) {
+			t.Errorf("SplitArgs(QuoteArgs(%q)) = %q, %v", tt.args, back, err)
+		}
+	}
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "known keys",
+			in:   `{"projects":{"shop":[{"name":"web","absolutePath":"/srv","commands":["npm start"]}]},"aliases":{"up":"start shop"}}`,
+			want: `{
+  "projects": {
+    "shop": [
+      {
+        "name": "web",
+        "absolutePath": "/srv",
+        "commands": [
+          "npm start"
+        ]
+      }
+    ]
+  },
+  "aliases": {
+    "up": "start shop"
+  }
+}
+`,
+		},
+		{
+			name: "unknown keys are kept after the known ones",
+			in:   `{"$schema":"https://example.com/vunat.json","projects":{},"zeta":{"b":[1,2]},"alpha":null}`,
+			want: `{
+  "projects": {},
+  "$schema": "https://example.com/vunat.json",
+  "alpha": null,
+  "zeta": {
+    "b": [
+      1,
+      2
+    ]
+  }
+}
+`,
+		},
+		{
+			name: "missing projects",
+			in:   `{}`,
+			want: "{\n  \"projects\": {}\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, err := Parse([]byte(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := conf.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// Process describes a process of the running project. Processes are
+// numbered from 1 in start order.
+type Process struct {
+	Number  int
+	Group   string
+	Command string
+	Oneshot bool
+	// Input is set for a process that WriteInput can write to.
+	Input    bool
+	PID      int
+	State    string
+	Started  time.Time
+	Restarts int
+}
+
+// session is the state of the run in progress that the interactive
+// controls act on.
+type session struct {
+	project  string
+	groups   []string
+	services func() []*service
+	restart  func(svc *service, reason string)
+	quit     func()
+	// ending reports whether the project is being stopped.
+	ending func() bool
+	// finished is set once Start has returned.
+	finished atomic.Bool
+}
+
+var errNotRunning = errors.New("no project is running")
+
+func (r *Runner) setSession(s *session) {
+	r.mu.Lock()
+	r.sess = s
+	r.mu.Unlock()
+}
+
+func (r *Runner) session() (*session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sess == nil || r.sess.restart == nil || r.sess.finished.Load() {
+		return nil, errNotRunning
+	}
+	return r.sess, nil
+}
+
+// Processes lists the processes started so far by the running project, or
+// by the last one once it has stopped.
+func (r *Runner) Processes() []Process {
+	r.mu.Lock()
+	s := r.sess
+	r.mu.Unlock()
+	if s == nil {
+		return nil
+	}
+	list := s.services()
+	out := make([]Process, 0, len(list))
+	for i, svc := range list {
+		svc.mu.Lock()
+		p := Process{
+			Number:   i + 1,
+			Group:    svc.group,
+			Command:  svc.command,
+			Oneshot:  svc.oneshot,
+			Input:    svc.input,
+			State:    svc.state(),
+			Started:  svc.start,
+			Restarts: svc.restarts,
+		}
+		if svc.cmd != nil && svc.cmd.Process != nil {
+			p.PID = svc.cmd.Process.Pid
+		}
+		svc.mu.Unlock()
+		out = append(out, p)
+	}
+	return out
+}
+
+// Groups returns the group names of the running project in start order.
+func (r *Runner) Groups() []string {
+	s, err := r.session()
+	if err != nil {
+		return nil
+	}
+	return s.groups
+}
+
+// Restart stops process number n gracefully and starts it again. It
+// returns once the process has been started.
+func (r *Runner) Restart(n int) error {
+	s, err := r.session()
+	if err != nil {
+		return err
+	}
+	list := s.services()
+	if n < 1 || n > len(list) {
+		return fmt.Errorf("no process %d (expected 1-%d)", n, len(list))
+	}
+	svc := list[n-1]
+	if svc.oneshot {
+		return fmt.Errorf("%s is a oneshot and cannot be restarted", svc.name())
+	}
+	if s.ending() {
+		return fmt.Errorf("the project is stopping")
+	}
+	// A process stopped on request (or by its group) may be started again.
+	svc.stopped.Store(false)
+	s.restart(svc, "restart requested")
+	return nil
+}
+
+// StopProcess stops process number n gracefully: it is asked to exit and
+// killed if it is still running after a grace period. Its exit does not
+// count as a failure; Restart starts it again.
+func (r *Runner) StopProcess(n int) error {
+	s, err := r.session()
+	if err != nil {
+		return err
+	}
+	list := s.services()
+	if n < 1 || n > len(list) {
+		return fmt.Errorf("no process %d (expected 1-%d)", n, len(list))
+	}
+	svc := list[n-1]
+	if svc.oneshot {
+		return fmt.Errorf("%s is a oneshot and cannot be stopped on its own", svc.name())
+	}
+	svc.mu.Lock()
+	running := !svc.start.IsZero() && svc.end.IsZero() && !svc.restarting
+	cmd, done := svc.cmd, svc.done
+	svc.mu.Unlock()
+	if !running || svc.stopped.Swap(true) {
+		return fmt.Errorf("%s is not running", svc.name())
+	}
+	r.emit(svc.event(ProcessStopping))
+	stopGracefully(cmd.Process, done, stopTimeout)
+	return nil
+}
+
+// Stop ends the running project gracefully: every service is asked to exit
+// and killed if it is still running after a grace period, then Start
+// returns as if they had exited on their own.
+func (r *Runner) Stop() error {
+	s, err := r.session()
+	if err != nil {
+		return err
+	}
+	s.quit()
+	return nil
+}
+
+// WriteSummary prints the end-of-run table of the last project started.
+func (r *Runner) WriteSummary(w io.Writer) {
+	r.mu.Lock()
+	s := r.sess
+	r.mu.Unlock()
+	if s != nil {
+		writeSummary(w, s.services())
+	}
+}
+
+// WriteStatus prints a table of the running project's processes with their
+// number, PID, uptime, state and restart count.
+func (r *Runner) WriteStatus(w io.Writer) error {
+	if _, err := r.session(); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  #\tPROCESS\tPID\tUPTIME\tSTATE\tRESTARTS")
+	for _, p := range r.Processes() {
+		pid, uptime := "-", "-"
+		if p.PID != 0 {
+			pid = strconv.Itoa(p.PID)
+		}
+		if p.State == "running" {
+			uptime = formatDuration(time.Since(p.Started))
+		}
+		fmt.Fprintf(tw, "  %d\t[%s] %s\t%s\t%s\t%s\t%d\n", p.Number, p.Group, p.Command, pid, uptime, p.State, p.Restarts)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,182 @@
+package runner
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
+)
+
+// EventKind identifies what an Event reports.
+type EventKind string
+
+// Event kinds.
+const (
+	// ProcessStarting is sent before a process is started.
+	ProcessStarting EventKind = "starting"
+	// ProcessStarted is sent once a process is running; PID is set.
+	ProcessStarted EventKind = "started"
+	// ProcessReady is sent once a process can be relied on: a service when
+	// its group has started (or right after a restart), a oneshot command
+	// when it has completed successfully.
+	ProcessReady EventKind = "ready"
+	// ProcessOutput carries one line a process wrote to Stream.
+	ProcessOutput EventKind = "output"
+	// ProcessExited is sent once a process has exited; Err and ExitCode
+	// tell how.
+	ProcessExited EventKind = "exited"
+	// ProcessRestarting is sent before a process is stopped to be started
+	// again; Line gives the reason.
+	ProcessRestarting EventKind = "restarting"
+	// ProcessStopping is sent when the runner stops a process.
+	ProcessStopping EventKind = "stopping"
+	// GroupStarting is sent before the commands of a group are started;
+	// Dir is its directory.
+	GroupStarting EventKind = "group-starting"
+	// GroupStarted is sent once every command of a group is running.
+	GroupStarted EventKind = "group-started"
+	// Message carries a line the runner itself reports, such as hook
+	// output or an exit policy decision.
+	Message EventKind = "message"
+)
+
+// Event is something that happened while a project runs. Process events
+// identify the process by its number (from 1 in start order, as in
+// Processes), group and command.
+type Event struct {
+	Time    time.Time
+	Kind    EventKind
+	Project string
+	Group   string
+	Process int
+	Command string
+	Oneshot bool
+	PID     int
+	// Dir is the directory of a starting group.
+	Dir string
+	// Stream is "stdout" or "stderr" for output and messages.
+	Stream string
+	// Line is an output line without its line ending, a message or a
+	// restart reason.
+	Line string
+	// Continued marks a chunk of an output line too long to pass whole;
+	// the line goes on in the next output event of the stream.
+	Continued bool
+	// Err is the error a process exited with, nil after a clean exit.
+	Err error
+	// ExitCode is the exit status of an exited process, -1 if it was
+	// killed by a signal or could not be waited for.
+	ExitCode int
+}
+
+// subscribers holds the sinks receiving a Runner's events, in the order
+// they subscribed. The list is replaced rather than modified, so emit can
+// call the sinks without holding mu.
+type subscribers struct {
+	mu    sync.Mutex
+	next  int
+	sinks []subscriber
+}
+
+type subscriber struct {
+	id   int
+	sink Sink
+}
+
+// Subscribe sends every event of the projects started by the Runner to s
+// until unsubscribe is called. The Runner writes nothing itself: what is
+// shown or recorded, and where, is up to its sinks, such as a Printer for
+// the terminal or a JSONLines log. The events of one process arrive in
+// order. A sink may subscribe or unsubscribe sinks from its Event; the
+// change applies from the next event on.
+func (r *Runner) Subscribe(s Sink) (unsubscribe func()) {
+	subs := &r.subs
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+	id := subs.next
+	subs.next++
+	subs.sinks = append(subs.sinks[:len(subs.sinks):len(subs.sinks)], subscriber{id: id, sink: s})
+	return func() {
+		subs.mu.Lock()
+		defer subs.mu.Unlock()
+		for i, sub := range subs.sinks {
+			if sub.id == id {
+				subs.sinks = append(subs.sinks[:i:i], subs.sinks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// emit sends e, stamped with the current time and project, to the
+// subscribers.
+func (r *Runner) emit(e Event) {
+	e.Time = time.Now()
+	r.mu.Lock()
+	if r.sess != nil {
+		e.Project = r.sess.project
+	}
+	r.mu.Unlock()
+	r.subs.mu.Lock()
+	sinks := r.subs.sinks
+	r.subs.mu.Unlock()
+	for _, s := range sinks {
+		s.sink.Event(e)
+	}
+}
+
+// event returns an event of kind about svc's current process.
+func (s *service) event(kind EventKind) Event {
+	e := Event{Kind: kind, Process: s.id, Group: s.group, Command: s.command, Oneshot: s.oneshot}
+	s.mu.Lock()
+	if s.cmd != nil && s.cmd.Process != nil {
+		e.PID = s.cmd.Process.Pid
+	}
+	s.mu.Unlock()
+	return e
+}
+
+// exitedEvent returns the ProcessExited event of svc's process ending with
+// err.
+func (s *service) exitedEvent(err error) Event {
+	e := s.event(ProcessExited)
+	e.Err = err
+	if err != nil {
+		e.ExitCode = exitcode.NewProcessError(s.name(), err).Code
+	}
+	return e
+}
+
+// say sends text, a runner message about svc (or the project when nil)
+// for stdout or, with toStderr, stderr, to subscribers.
+func (r *Runner) say(svc *service, toStderr bool, text string) {
+	e := Event{Kind: Message}
+	if svc != nil {
+		e = svc.event(Message)
+	}
+	e.Stream = "stdout"
+	if toStderr {
+		e.Stream = "stderr"
+	}
+	e.Line = strings.TrimRight(text, "\n")
+	r.emit(e)
+}
+
+// lineEmitter returns a function sending lines svc wrote to stream as
+// ProcessOutput events.
+func (r *Runner) lineEmitter(svc *service, stream string) func(string, bool) {
+	return func(line string, continued bool) {
+		e := svc.event(ProcessOutput)
+		e.Stream, e.Line, e.Continued = stream, line, continued
+		r.emit(e)
+	}
+}
+
+// messageEmitter returns a function sending lines written to stream by a
+// hook as Message events, with the hook's prefix.
+func (r *Runner) messageEmitter(prefix, stream string) func(string, bool) {
+	return func(line string, continued bool) {
+		r.emit(Event{Kind: Message, Stream: stream, Line: prefix + line, Continued: continued})
+	}
+}
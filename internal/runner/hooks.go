@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// defaultHookTimeout bounds each hook command when no hookTimeout is set.
+const defaultHookTimeout = 10 * time.Minute
+
+// hookPolicy holds the effective timeout and failure policy for a scope.
+type hookPolicy struct {
+	timeout time.Duration
+	abort   bool
+}
+
+// policyFor resolves the hook settings of h, falling back to parent for
+// anything h leaves unset.
+func policyFor(h projects.Hooks, parent hookPolicy) (hookPolicy, error) {
+	p := parent
+	if h.HookTimeout != "" {
+		d, err := time.ParseDuration(h.HookTimeout)
+		if err != nil || d <= 0 {
+			return p, fmt.Errorf("invalid hookTimeout %q: expected a duration such as \"2m\"", h.HookTimeout)
+		}
+		p.timeout = d
+	}
+	switch h.HookFailure {
+	case "":
+	case projects.HookAbort:
+		p.abort = true
+	case projects.HookContinue:
+		p.abort = false
+	default:
+		return p, fmt.Errorf("invalid hookFailure %q: expected %q or %q", h.HookFailure, projects.HookAbort, projects.HookContinue)
+	}
+	return p, nil
+}
+
+// checkHooks reports the first hook command of h that does not split into
+// arguments.
+func checkHooks(h projects.Hooks) error {
+	for _, hooks := range []struct {
+		kind string
+		cmds []string
+	}{{"before", h.Before}, {"beforeEach", h.BeforeEach}, {"after", h.After}, {"afterStop", h.AfterStop}} {
+		for _, cmd := range hooks.cmds {
+			if _, err := projects.SplitArgs(cmd); err != nil {
+				return fmt.Errorf("%s hook: %w", hooks.kind, err)
+			}
+		}
+	}
+	return nil
+}
+
+// hookScope is where a set of hooks runs and how their output is labelled.
+type hookScope struct {
+	name   string
+	dir    string
+	env    map[string]string
+	policy hookPolicy
+}
+
+// runHooks runs cmds one after another, each to completion within the
+// scope's timeout, with output prefixed by "[<scope>:<kind>] ". A failing
+// hook stops the sequence and returns an error when the scope's policy is
+// abort; otherwise it is reported and the remaining hooks still run. Once
+// ctx is done no further hook is started.
+func (r *Runner) runHooks(ctx context.Context, s hookScope, kind string, cmds []string) error {
+	prefix := fmt.Sprintf("[%s:%s] ", s.name, kind)
+	for _, cmdStr := range cmds {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := r.runHook(ctx, s, prefix, cmdStr)
+		if err == nil {
+			continue
+		}
+		if s.policy.abort {
+			return exitcode.NewProcessError(fmt.Sprintf("%s hook %q of %s", kind, cmdStr, s.name), err)
+		}
+		r.say(nil, true, fmt.Sprintf("%shook %q failed: %v (continuing)\n", prefix, cmdStr, err))
+	}
+	return nil
+}
+
+func (r *Runner) runHook(ctx context.Context, s hookScope, prefix, cmdStr string) error {
+	parts := splitFields(cmdStr)
+	if len(parts) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.policy.timeout)
+	defer cancel()
+
+	r.say(nil, false, fmt.Sprintf("%s$ %s\n", prefix, cmdStr))
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	cmd.Dir = s.dir
+	if len(s.env) > 0 {
+		cmd.Env = append(os.Environ(), envList(s.env)...)
+	}
+	stdout := &lineWriter{line: r.messageEmitter(prefix, "stdout")}
+	stderr := &lineWriter{line: r.messageEmitter(prefix, "stderr")}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	// Like a process, the hook and whatever it spawned are killed together
+	// on timeout or cancellation.
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return kill(cmd.Process) }
+	// Don't let a background child holding the pipes open outlive the hook.
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	stdout.Flush()
+	stderr.Flush()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", s.policy.timeout)
+	}
+	return err
+}
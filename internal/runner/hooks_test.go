@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestHooksCancelled checks that cancelling a hook kills its whole process
+// group and that no later hook starts.
+func TestHooksCancelled(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	writeScript(t, dir, "hook.sh", "(sleep 1; touch child) &\nsleep 30")
+	s := hookScope{name: "test", dir: dir, policy: hookPolicy{timeout: time.Minute}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	start := time.Now()
+	if err := New().runHooks(ctx, s, "afterStop", []string{"sh hook.sh", "touch second"}); err == nil {
+		t.Fatal("runHooks() = nil, want an error")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("runHooks() took %s after cancellation", d)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	for _, name := range []string{"child", "second"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Errorf("%s was created after cancellation", name)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// SetStdinTarget makes the first service of group, or its service with
+// "stdin" set, the process reading vunat's input in the projects started
+// from now on, in place of the command with "stdin" set.
+func (r *Runner) SetStdinTarget(group string) {
+	r.mu.Lock()
+	r.attach = group
+	r.mu.Unlock()
+}
+
+// stdinTarget finds the service reading vunat's input: the one chosen for
+// the group attach, or else the command with stdin set. It returns its
+// group and its position among the group's services, gi -1 if there is
+// none.
+func stdinTarget(proj projects.Project, attach string) (gi, si int, err error) {
+	gi = -1
+	for i, g := range proj.Groups {
+		for _, c := range g.Commands {
+			if c.Stdin && c.Oneshot() {
+				return -1, 0, fmt.Errorf("group %q: oneshot %q cannot read stdin", g.Name, c.Command)
+			}
+		}
+		for n, c := range services(g) {
+			if !c.Stdin {
+				continue
+			}
+			if gi >= 0 {
+				return -1, 0, fmt.Errorf("group %q: only one command can set stdin; %q does too", g.Name, c.Command)
+			}
+			gi, si = i, n
+		}
+	}
+	if attach == "" {
+		return gi, si, nil
+	}
+	for i, g := range proj.Groups {
+		if g.Name != attach {
+			continue
+		}
+		list := services(g)
+		if len(list) == 0 {
+			return -1, 0, fmt.Errorf("group %q has no service to attach stdin to", attach)
+		}
+		for n, c := range list {
+			if c.Stdin {
+				return i, n, nil
+			}
+		}
+		return i, 0, nil
+	}
+	return -1, 0, fmt.Errorf("no group %q to attach stdin to", attach)
+}
+
+// services returns the commands of g that Start runs as services.
+func services(g projects.CommandGroup) []projects.Command {
+	var out []projects.Command
+	for _, c := range g.Commands {
+		if !c.Oneshot() && len(splitFields(c.Command)) > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// InputTarget returns the number of the process reading vunat's input, 0
+// if the running project has none or it has not been started yet.
+func (r *Runner) InputTarget() int {
+	s, err := r.session()
+	if err != nil {
+		return 0
+	}
+	for _, svc := range s.services() {
+		if svc.target {
+			return svc.id
+		}
+	}
+	return 0
+}
+
+// WriteInput writes p to the stdin of process number n, which must take
+// input: a tty command, one with stdin set or the one chosen with
+// SetStdinTarget.
+func (r *Runner) WriteInput(n int, p []byte) error {
+	svc, w, err := r.input(n)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(p); err != nil {
+		return fmt.Errorf("%s: %w", svc.name(), err)
+	}
+	return nil
+}
+
+// CloseInput closes the stdin of process number n, so that it reads the
+// end of its input; a tty command is left alone.
+func (r *Runner) CloseInput(n int) error {
+	svc, w, err := r.input(n)
+	if err != nil || svc.tty {
+		return err
+	}
+	return w.(io.Closer).Close()
+}
+
+// input returns process number n and the writer to its stdin.
+func (r *Runner) input(n int) (*service, io.Writer, error) {
+	s, err := r.session()
+	if err != nil {
+		return nil, nil, err
+	}
+	list := s.services()
+	if n < 1 || n > len(list) {
+		return nil, nil, fmt.Errorf("no process %d (expected 1-%d)", n, len(list))
+	}
+	svc := list[n-1]
+	if !svc.input {
+		return nil, nil, fmt.Errorf("%s does not read input; set \"stdin\": true or use --attach", svc.name())
+	}
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	switch {
+	case svc.pty != nil:
+		return svc, svc.pty, nil
+	case svc.stdin != nil:
+		return svc, svc.stdin, nil
+	}
+	return nil, nil, fmt.Errorf("%s is not running", svc.name())
+}
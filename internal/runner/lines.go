@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// maxLineLength is the longest line passed on whole; longer lines are
+// passed in chunks of this size, all but the last marked as continued.
+const maxLineLength = 64 * 1024
+
+// ContinuationMarker is shown at the end of a chunk of a long line that
+// continues in the next event.
+const ContinuationMarker = " ↵"
+
+// lineWriter splits what a process writes into lines and passes each,
+// without its line ending, to line. It never fails or blocks the writer on
+// its own, so the process's pipe is always drained:
+//   - a line longer than maxLineLength is passed in chunks, continued set on all but the last;
+//   - a carriage return not followed by a newline starts the line over, so a progress bar yields only its final state;
+//   - invalid UTF-8 is replaced by U+FFFD;
+//   - Flush passes a final unterminated line.
+type lineWriter struct {
+	mu   sync.Mutex
+	line func(text string, continued bool)
+	buf  []byte
+	// cr is set after a carriage return whose next byte is still unknown.
+	cr bool
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, c := range p {
+		if w.cr {
+			w.cr = false
+			if c != '\n' {
+				// Redrawn line: drop what it showed before.
+				w.buf = w.buf[:0]
+			}
+		}
+		switch c {
+		case '\n':
+			w.emit(w.buf, false)
+			w.buf = w.buf[:0]
+		case '\r':
+			w.cr = true
+		default:
+			w.buf = append(w.buf, c)
+			if len(w.buf) >= maxLineLength {
+				w.chunk()
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// chunk passes the start of an overlong line as a continued chunk, cut at
+// a character boundary, and keeps the rest.
+func (w *lineWriter) chunk() {
+	n := len(w.buf)
+	for i := n; i > n-utf8.UTFMax && i > 0; i-- {
+		if utf8.RuneStart(w.buf[i-1]) {
+			if !utf8.FullRune(w.buf[i-1:]) {
+				n = i - 1
+			}
+			break
+		}
+	}
+	if n == 0 {
+		n = len(w.buf)
+	}
+	w.emit(w.buf[:n], true)
+	w.buf = append(w.buf[:0], w.buf[n:]...)
+}
+
+func (w *lineWriter) emit(b []byte, continued bool) {
+	w.line(strings.ToValidUTF8(string(b), "�"), continued)
+}
+
+// Flush passes the final line if it was not terminated.
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.emit(w.buf, false)
+		w.buf = w.buf[:0]
+	}
+	w.cr = false
+}
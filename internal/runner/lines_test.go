@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	type line struct {
+		text      string
+		continued bool
+	}
+	long := strings.Repeat("x", maxLineLength)
+	tests := []struct {
+		name   string
+		writes []string
+		want   []line
+	}{
+		{name: "lines", writes: []string{"a\nb\n"}, want: []line{{"a", false}, {"b", false}}},
+		{name: "empty lines", writes: []string{"\n\n"}, want: []line{{"", false}, {"", false}}},
+		{name: "split writes", writes: []string{"he", "llo\nwor", "ld"}, want: []line{{"hello", false}, {"world", false}}},
+		{name: "crlf", writes: []string{"a\r\nb\r", "\n"}, want: []line{{"a", false}, {"b", false}}},
+		{name: "progress bar", writes: []string{"10%\r50%", "\r100%\n"}, want: []line{{"100%", false}}},
+		{name: "trailing carriage return", writes: []string{"done\r"}, want: []line{{"done", false}}},
+		{name: "invalid utf-8", writes: []string{"a\xffb\n"}, want: []line{{"a�b", false}}},
+		{name: "utf-8 split across writes", writes: []string{"caf\xc3", "\xa9\n"}, want: []line{{"café", false}}},
+		{
+			name:   "long line",
+			writes: []string{long, "tail\n"},
+			want:   []line{{long, true}, {"tail", false}},
+		},
+		{
+			name:   "long line cut before a character",
+			writes: []string{long[1:] + "é\n"},
+			want:   []line{{long[1:], true}, {"é", false}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []line
+			w := &lineWriter{line: func(text string, continued bool) { got = append(got, line{text, continued}) }}
+			for _, s := range tt.writes {
+				if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+					t.Fatalf("Write() = %d, %v", n, err)
+				}
+			}
+			w.Flush()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lines = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// exitPolicy is the effective onFailure/onExit setting of a group.
+type exitPolicy struct {
+	onFailure string
+	onExit    string
+}
+
+// defaultExitPolicy stops everything when a service fails and keeps running
+// when one exits cleanly.
+var defaultExitPolicy = exitPolicy{onFailure: projects.FailureStopAll, onExit: projects.ExitIgnore}
+
+// exitPolicyFor resolves the settings of p, falling back to parent for
+// anything p leaves unset.
+func exitPolicyFor(p projects.Policy, parent exitPolicy) (exitPolicy, error) {
+	out := parent
+	switch p.OnFailure {
+	case "":
+	case projects.FailureStopAll, projects.FailureStopGroup, projects.FailureContinue:
+		out.onFailure = p.OnFailure
+	default:
+		return out, fmt.Errorf("invalid onFailure %q: expected %q, %q or %q", p.OnFailure, projects.FailureStopAll, projects.FailureStopGroup, projects.FailureContinue)
+	}
+	switch p.OnExit {
+	case "":
+	case projects.ExitStopAll, projects.ExitIgnore:
+		out.onExit = p.OnExit
+	default:
+		return out, fmt.Errorf("invalid onExit %q: expected %q or %q", p.OnExit, projects.ExitStopAll, projects.ExitIgnore)
+	}
+	return out, nil
+}
+
+// service is a process started by Start, tracked for the exit policies and
+// the end-of-run summary.
+type service struct {
+	group string
+	// id numbers the processes of a run from 1 in start order.
+	id int
+	// gi is the index of the group in the project.
+	gi      int
+	command string
+	oneshot bool
+	// tty runs the process under a pseudo-terminal.
+	tty bool
+	// input is set for a process whose stdin can be written to; target
+	// for the one reading vunat's input.
+	input, target bool
+	// stopped is set when the runner stops the process on purpose, so its
+	// exit is not reported as a failure.
+	stopped atomic.Bool
+	// stopWatching ends the file watcher of a watched service.
+	stopWatching func()
+
+	// mu guards the fields below, which change when the service restarts.
+	mu  sync.Mutex
+	cmd *exec.Cmd
+	// done is closed once the current process has been waited for.
+	done chan struct{}
+	// pty is the pseudo-terminal of the current process when tty is set.
+	pty *os.File
+	// stdin is the pipe to the current process when input is set, without
+	// a pseudo-terminal.
+	stdin io.WriteCloser
+	// restarting is set while the runner restarts the process, so its exit
+	// is not handled by the exit policies.
+	restarting bool
+	// failed is set when the process's exit counts as a failure of the run.
+	failed   bool
+	restarts int
+	start    time.Time
+	end      time.Time
+	err      error
+}
+
+// name identifies the process in messages, e.g. "[api] go run ./cmd/api".
+func (s *service) name() string {
+	return fmt.Sprintf("[%s] %s", s.group, s.command)
+}
+
+// stop kills the process and ends its file watcher; killing one that has
+// already exited is harmless.
+func (s *service) stop() {
+	s.stopped.Store(true)
+	if s.stopWatching != nil {
+		s.stopWatching()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cmd != nil && s.cmd.Process != nil {
+		_ = kill(s.cmd.Process)
+	}
+}
+
+// stopTimeout is how long a process may take to exit after being asked to
+// before it is killed.
+const stopTimeout = 5 * time.Second
+
+// stopGracefully asks p to exit and kills it if it is still running after
+// timeout. done must be closed once p has been waited for.
+func stopGracefully(p *os.Process, done <-chan struct{}, timeout time.Duration) {
+	if err := terminate(p); err != nil {
+		_ = kill(p)
+	}
+	select {
+	case <-done:
+		return
+	case <-time.After(timeout):
+	}
+	_ = kill(p)
+	<-done
+}
+
+// state describes the process while the project runs: "running",
+// "restarting" or, once it has exited, its status.
+func (s *service) state() string {
+	switch {
+	case s.restarting:
+		return "restarting"
+	case !s.start.IsZero() && s.end.IsZero():
+		return "running"
+	}
+	return s.status()
+}
+
+// status describes how the process ended for the summary.
+func (s *service) status() string {
+	switch {
+	case s.start.IsZero() && s.err != nil:
+		return "failed to start"
+	case s.start.IsZero():
+		return "not started"
+	case s.end.IsZero():
+		return "running"
+	case s.err == nil && s.oneshot:
+		return "completed"
+	case s.stopped.Load() && !s.failed:
+		return "stopped"
+	case s.err == nil:
+		return "exited 0"
+	}
+	e := exitcode.NewProcessError(s.name(), s.err)
+	switch {
+	case e.Signal != "":
+		return "signal: " + e.Signal
+	case e.Code >= 0:
+		return fmt.Sprintf("exited %d", e.Code)
+	}
+	return "failed to start"
+}
+
+// writeSummary prints a table of every process with its PID, runtime, exit
+// status and restart count.
+func writeSummary(w io.Writer, list []*service) {
+	if len(list) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nSummary:\n")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  PROCESS\tPID\tRUNTIME\tSTATUS\tRESTARTS")
+	for _, s := range list {
+		pid, runtime := "-", "-"
+		if s.cmd != nil && s.cmd.Process != nil {
+			pid = strconv.Itoa(s.cmd.Process.Pid)
+		}
+		if !s.start.IsZero() && !s.end.IsZero() {
+			runtime = formatDuration(s.end.Sub(s.start))
+		}
+		status := s.status()
+		if s.failed {
+			status += " (failed)"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%d\n", s.name(), pid, runtime, status, s.restarts)
+	}
+	_ = tw.Flush()
+}
+
+// formatDuration rounds d for display: milliseconds below a second, whole
+// seconds above.
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
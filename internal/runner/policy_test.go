@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+func TestExitPolicyFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       projects.Policy
+		parent  exitPolicy
+		want    exitPolicy
+		wantErr bool
+	}{
+		{name: "inherited", parent: defaultExitPolicy, want: defaultExitPolicy},
+		{
+			name:   "overridden",
+			p:      projects.Policy{OnFailure: projects.FailureContinue, OnExit: projects.ExitStopAll},
+			parent: defaultExitPolicy,
+			want:   exitPolicy{onFailure: projects.FailureContinue, onExit: projects.ExitStopAll},
+		},
+		{
+			name:   "partly overridden",
+			p:      projects.Policy{OnFailure: projects.FailureStopGroup},
+			parent: exitPolicy{onFailure: projects.FailureContinue, onExit: projects.ExitStopAll},
+			want:   exitPolicy{onFailure: projects.FailureStopGroup, onExit: projects.ExitStopAll},
+		},
+		{name: "invalid onFailure", p: projects.Policy{OnFailure: "restart"}, parent: defaultExitPolicy, wantErr: true},
+		{name: "invalid onExit", p: projects.Policy{OnExit: "stop-group"}, parent: defaultExitPolicy, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := exitPolicyFor(tt.p, tt.parent)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: exitPolicyFor() = %+v, want an error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: exitPolicyFor() = %+v, %v; want %+v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+// TestExitPolicies runs projects in which a service fails or exits and
+// checks what happens to the others.
+func TestExitPolicies(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	group := func(name string, p projects.Policy, cmds ...string) projects.CommandGroup {
+		return projects.CommandGroup{Name: name, Commands: projects.NewCommands(cmds...), Policy: p}
+	}
+	tests := []struct {
+		name    string
+		proj    projects.Project
+		wantErr bool
+		// states maps "group command" to the final state of the process.
+		states map[string]string
+	}{
+		{
+			name:    "failure stops all by default",
+			proj:    projects.Project{Groups: []projects.CommandGroup{group("a", projects.Policy{}, "sh fail.sh", "sh long.sh"), group("b", projects.Policy{}, "sh long.sh")}},
+			wantErr: true,
+			states:  map[string]string{"a sh fail.sh": "exited 3", "a sh long.sh": "stopped", "b sh long.sh": "stopped"},
+		},
+		{
+			name: "failure stops the group",
+			proj: projects.Project{Groups: []projects.CommandGroup{
+				group("a", projects.Policy{OnFailure: projects.FailureStopGroup}, "sh fail.sh", "sh long.sh"),
+				group("b", projects.Policy{}, "sh ok.sh"),
+			}},
+			wantErr: true,
+			states:  map[string]string{"a sh fail.sh": "exited 3", "a sh long.sh": "stopped", "b sh ok.sh": "exited 0"},
+		},
+		{
+			name:    "failure is tolerated",
+			proj:    projects.Project{Groups: []projects.CommandGroup{group("a", projects.Policy{OnFailure: projects.FailureContinue}, "sh fail.sh", "sh ok.sh")}},
+			wantErr: true,
+			states:  map[string]string{"a sh fail.sh": "exited 3", "a sh ok.sh": "exited 0"},
+		},
+		{
+			name:   "exit stops all",
+			proj:   projects.Project{Policy: projects.Policy{OnExit: projects.ExitStopAll}, Groups: []projects.CommandGroup{group("a", projects.Policy{}, "sh ok.sh", "sh long.sh")}},
+			states: map[string]string{"a sh ok.sh": "exited 0", "a sh long.sh": "stopped"},
+		},
+		{
+			name:   "exit is ignored by default",
+			proj:   projects.Project{Groups: []projects.CommandGroup{group("a", projects.Policy{}, "sh ok.sh", "sh ok.sh")}},
+			states: map[string]string{"a sh ok.sh": "exited 0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeScript(t, dir, "fail.sh", "sleep 0.2; exit 3")
+			writeScript(t, dir, "ok.sh", "sleep 0.5")
+			writeScript(t, dir, "long.sh", "exec sleep 30")
+			for i := range tt.proj.Groups {
+				tt.proj.Groups[i].AbsolutePath = dir
+			}
+			r := New()
+			done := make(chan error, 1)
+			go func() { done <- r.Start(context.Background(), tt.proj) }()
+			select {
+			case err := <-done:
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Start() = %v, want failure %v", err, tt.wantErr)
+				}
+			case <-time.After(15 * time.Second):
+				t.Fatal("project did not finish")
+			}
+			procs, n := r.Processes(), 0
+			for _, g := range tt.proj.Groups {
+				n += len(g.Commands)
+			}
+			if len(procs) != n {
+				t.Fatalf("%d processes, want %d", len(procs), n)
+			}
+			for _, p := range procs {
+				if want := tt.states[p.Group+" "+p.Command]; p.State != want {
+					t.Errorf("[%s] %s: state %q, want %q", p.Group, p.Command, p.State, want)
+				}
+			}
+		})
+	}
+}
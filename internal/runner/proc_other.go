@@ -0,0 +1,30 @@
+//go:build !unix
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on platforms without process groups, such as
+// Windows, where processes are stopped individually.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// setControllingTerminal is a no-op: pseudo-terminals are not supported.
+func setControllingTerminal(cmd *exec.Cmd) {}
+
+// terminate stops p; there is no portable signal asking a process to exit.
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
+
+// groupAlive reports false: without process groups nothing outlives p.
+func groupAlive(p *os.Process) bool {
+	return false
+}
+
+// kill stops p immediately.
+func kill(p *os.Process) error {
+	return p.Kill()
+}
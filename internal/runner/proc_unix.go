@@ -0,0 +1,51 @@
+//go:build unix
+
+package runner
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in a process group of its own, so stopping it
+// also stops the processes it spawned (such as the binary built by
+// `go run`).
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// setControllingTerminal starts cmd in a session of its own with its stdin,
+// a pseudo-terminal, as the controlling terminal. The session's process
+// group is stopped as a whole like the one of setProcessGroup.
+func setControllingTerminal(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true, Ctty: 0}
+}
+
+// terminate asks p and its process group to exit.
+func terminate(p *os.Process) error {
+	return signalGroup(p, syscall.SIGTERM)
+}
+
+// groupAlive reports whether p's process group still has members, such as
+// children left behind by p after it exited.
+func groupAlive(p *os.Process) bool {
+	return syscall.Kill(-p.Pid, 0) == nil
+}
+
+// kill stops p and its process group immediately.
+func kill(p *os.Process) error {
+	return signalGroup(p, syscall.SIGKILL)
+}
+
+func signalGroup(p *os.Process, sig syscall.Signal) error {
+	if err := syscall.Kill(-p.Pid, sig); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+		// Not a group leader (e.g. the group could not be created).
+		return p.Signal(sig)
+	}
+	return nil
+}
@@ -1,29 +1,46 @@
 package runner
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
-	"strings"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
 	"github.com/tanuvnair/vunat-cli/internal/projects"
+	"github.com/tanuvnair/vunat-cli/internal/watch"
 )
 
 // Runner supervises processes started for a project.
 type Runner struct {
 	mu    sync.Mutex
 	procs []*exec.Cmd
+	// sess is the run in progress, or the last one, for the interactive
+	// controls and the summary.
+	sess *session
+	subs subscribers
+	// cols and rows size the pseudo-terminals of tty commands.
+	cols, rows int
+	// attach names the group whose service reads vunat's input.
+	attach string
 }
 
-// New creates a new Runner.
-func New() *Runner {
-	return &Runner{
+// New creates a new Runner sending its events to sinks; more can be added
+// with Subscribe.
+func New(sinks ...Sink) *Runner {
+	r := &Runner{
 		procs: make([]*exec.Cmd, 0, 8),
+		cols:  defaultCols,
+		rows:  defaultRows,
 	}
+	for _, s := range sinks {
+		r.Subscribe(s)
+	}
+	return r
 }
 
 // StartByName looks up the project by name and starts it via Runner.Start.
@@ -34,95 +51,401 @@ func (r *Runner) StartByName(ctx context.Context, name string) error {
 	if err != nil {
 		return err
 	}
-	return r.Start(ctx, proj)
+	return r.start(ctx, name, proj)
 }
 
 // Start launches all command groups in the provided project.
 // - Groups are started sequentially; commands within a group are started concurrently.
-// - Each command is started with exec.CommandContext so it is cancelled when ctx is done.
+// - Runner.Stop ends the project gracefully; cancelling ctx kills every process at once.
+// - Hooks run to completion around the processes; afterStop hooks run however Start returns.
+// - A service's exit is handled by its group's onFailure/onExit policy; failures are listed at the end.
+// - A service with watch settings is stopped gracefully and started again when its files change.
+// - Nothing is written: output and lifecycle changes are sent to subscribers as events.
 // - Returns nil if all processes exit cleanly, or the first non-nil error encountered.
 func (r *Runner) Start(ctx context.Context, proj projects.Project) error {
+	return r.start(ctx, "", proj)
+}
+
+// start is Start for the project registered as name, which its events
+// carry; name is empty for an unregistered project.
+func (r *Runner) start(ctx context.Context, name string, proj projects.Project) error {
 	// derive cancellable context so we can cancel on first error
+	parent := ctx
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// Project hooks run in the first group's directory and environment.
+	projScope := hookScope{name: "project"}
+	if len(proj.Groups) > 0 {
+		projScope.dir, projScope.env = proj.Groups[0].AbsolutePath, proj.Groups[0].Env
+	}
+	var err error
+	if projScope.policy, err = policyFor(proj.Hooks, hookPolicy{timeout: defaultHookTimeout, abort: true}); err != nil {
+		return &exitcode.ConfigError{Err: err}
+	}
+	if err := checkHooks(proj.Hooks); err != nil {
+		return &exitcode.ConfigError{Err: err}
+	}
+	projPolicy, err := exitPolicyFor(proj.Policy, defaultExitPolicy)
+	if err != nil {
+		return &exitcode.ConfigError{Err: err}
+	}
+	groupScopes := make([]hookScope, len(proj.Groups))
+	groupPolicies := make([]exitPolicy, len(proj.Groups))
+	for i, group := range proj.Groups {
+		if groupPolicies[i], err = exitPolicyFor(group.Policy, projPolicy); err != nil {
+			return &exitcode.ConfigError{Err: fmt.Errorf("group %q: %w", group.Name, err)}
+		}
+		groupScopes[i] = hookScope{name: group.Name, dir: group.AbsolutePath, env: group.Env}
+		if groupScopes[i].policy, err = policyFor(group.Hooks, projScope.policy); err != nil {
+			return &exitcode.ConfigError{Err: fmt.Errorf("group %q: %w", group.Name, err)}
+		}
+		if err := checkHooks(group.Hooks); err != nil {
+			return &exitcode.ConfigError{Err: fmt.Errorf("group %q: %w", group.Name, err)}
+		}
+		for _, c := range group.Commands {
+			if _, err := projects.SplitArgs(c.Command); err != nil {
+				return &exitcode.ConfigError{Err: fmt.Errorf("group %q: %w", group.Name, err)}
+			}
+			if c.Kind != "" && c.Kind != projects.KindService && c.Kind != projects.KindOneshot {
+				return &exitcode.ConfigError{Err: fmt.Errorf("group %q: command %q has unknown kind %q (expected %q or %q)", group.Name, c.Command, c.Kind, projects.KindService, projects.KindOneshot)}
+			}
+			if c.Watch == nil {
+				continue
+			}
+			if c.Oneshot() {
+				return &exitcode.ConfigError{Err: fmt.Errorf("group %q: oneshot %q cannot watch files", group.Name, c.Command)}
+			}
+			if _, err := watcherFor(group, c.Watch); err != nil {
+				return &exitcode.ConfigError{Err: fmt.Errorf("group %q: command %q: watch: %w", group.Name, c.Command, err)}
+			}
+		}
+	}
+
+	r.mu.Lock()
+	attach := r.attach
+	r.mu.Unlock()
+	targetGroup, targetService, err := stdinTarget(proj, attach)
+	if err != nil {
+		return &exitcode.ConfigError{Err: err}
+	}
+
 	// channel for first process error
 	errCh := make(chan error, 1)
+	// closed when the project is stopped without a failure (onExit:
+	// stop-all or Runner.Stop)
+	stopCh := make(chan struct{})
+	var stopOnce sync.Once
+	// quitting reports whether stopCh is closed; errors of startup steps cut
+	// short by it are not failures.
+	quitting := func() bool {
+		select {
+		case <-stopCh:
+			return true
+		default:
+			return false
+		}
+	}
 
 	var wg sync.WaitGroup
+	var svcMu sync.Mutex
+	// all lists every process in start order for the summary.
+	var all []*service
+	groupServices := make(map[int][]*service)
+	track := func(gi int, svc *service) {
+		svcMu.Lock()
+		defer svcMu.Unlock()
+		all = append(all, svc)
+		svc.id = len(all)
+		if !svc.oneshot {
+			groupServices[gi] = append(groupServices[gi], svc)
+		}
+	}
+	stopGroup := func(gi int) {
+		svcMu.Lock()
+		defer svcMu.Unlock()
+		for _, s := range groupServices[gi] {
+			s.mu.Lock()
+			running := s.end.IsZero()
+			s.mu.Unlock()
+			if running && !s.stopped.Load() {
+				r.emit(s.event(ProcessStopping))
+			}
+			s.stop()
+		}
+	}
+
+	sess := &session{
+		project: name,
+		groups:  make([]string, 0, len(proj.Groups)),
+		services: func() []*service {
+			svcMu.Lock()
+			defer svcMu.Unlock()
+			return append([]*service(nil), all...)
+		},
+	}
+	for _, g := range proj.Groups {
+		sess.groups = append(sess.groups, g.Name)
+	}
+	r.setSession(sess)
+
+	// Stop whatever has been started and run the afterStop hooks (groups in
+	// reverse order, then the project) on every return path. They are cut
+	// short when parent is done, which is how callers kill the project.
+	started := 0
+	defer func() {
+		sess.finished.Store(true)
+		cancel()
+		_ = r.shutdownAll()
+		wg.Wait()
+		for i := started - 1; i >= 0; i-- {
+			_ = r.runHooks(parent, groupScopes[i], "afterStop", proj.Groups[i].AfterStop)
+		}
+		_ = r.runHooks(parent, projScope, "afterStop", proj.AfterStop)
+	}()
+
+	// launch starts the process of svc and waits for it in the background,
+	// applying the group's exit policy when it exits on its own.
+	launch := func(svc *service) error {
+		group, gi := proj.Groups[svc.gi], svc.gi
+		cmd := r.command(ctx, group, svc.command)
+		r.emit(svc.event(ProcessStarting))
+		wait, err := r.startProcess(cmd, svc)
+		if err != nil {
+			r.emit(svc.exitedEvent(err))
+			return exitcode.NewProcessError(svc.name(), err)
+		}
+		done := make(chan struct{})
+		svc.mu.Lock()
+		svc.cmd, svc.done = cmd, done
+		svc.start, svc.end, svc.err = time.Now(), time.Time{}, nil
+		svc.mu.Unlock()
+		r.emit(svc.event(ProcessStarted))
+
+		// record process for later shutdown
+		r.addProc(cmd)
 
-	// Helper to stream output
-	startStream := func(rc io.ReadCloser, out io.Writer, prefix string) {
+		// wait for process in background
+		prefix := fmt.Sprintf("[%s] ", group.Name)
+		policy := groupPolicies[gi]
+		wg.Add(1)
 		go func() {
-			defer rc.Close()
-			scanner := bufio.NewScanner(rc)
-			for scanner.Scan() {
-				fmt.Fprintln(out, prefix+scanner.Text())
+			defer wg.Done()
+			err := wait()
+			r.removeProc(cmd)
+			svc.mu.Lock()
+			svc.end, svc.err = time.Now(), err
+			restarting := svc.restarting
+			svc.mu.Unlock()
+			close(done)
+			r.emit(svc.exitedEvent(err))
+			if ctx.Err() != nil {
+				svc.stopped.Store(true)
+			}
+			if svc.stopped.Load() || restarting {
+				// stopped by the runner; not a failure of its own
+				return
+			}
+			if err == nil {
+				if policy.onExit == projects.ExitStopAll {
+					r.say(svc, false, fmt.Sprintf("%s%s exited; stopping project (onExit: %s)\n", prefix, svc.command, policy.onExit))
+					stopOnce.Do(func() { close(stopCh) })
+					cancel()
+					_ = r.shutdownAll()
+				}
+				return
+			}
+			svc.mu.Lock()
+			svc.failed = true
+			svc.mu.Unlock()
+			switch policy.onFailure {
+			case projects.FailureContinue:
+				r.say(svc, true, fmt.Sprintf("%s%s exited with error: %v (onFailure: continue)\n", prefix, svc.command, err))
+			case projects.FailureStopGroup:
+				r.say(svc, true, fmt.Sprintf("%s%s exited with error: %v; stopping group (onFailure: stop-group)\n", prefix, svc.command, err))
+				stopGroup(gi)
+			default:
+				// Try to send the first error observed; do not block if channel already has an error.
+				select {
+				case errCh <- exitcode.NewProcessError(svc.name(), err):
+					// cancel context so other processes get signaled
+					cancel()
+					// attempt to shutdown remaining processes
+					_ = r.shutdownAll()
+				default:
+					// already have an error, nothing to do
+				}
 			}
-			// ignore scanner error here; process Wait will surface failure
 		}()
+		return nil
 	}
 
-	// Start groups sequentially
-	for _, group := range proj {
-		fmt.Printf("[%s] Starting in: %s\n", group.Name, group.AbsolutePath)
+	// restart stops svc gracefully, waits for it to exit and starts it
+	// again; a service that has already exited is just started.
+	restart := func(svc *service, reason string) {
+		if svc.stopped.Load() || ctx.Err() != nil {
+			return
+		}
+		// Keep the run going while svc is down between its two runs;
+		// otherwise restarting the last service would end the project.
+		wg.Add(1)
+		defer wg.Done()
+		prefix := fmt.Sprintf("[%s] ", svc.group)
+		ev := svc.event(ProcessRestarting)
+		ev.Line = reason
+		r.emit(ev)
+		svc.mu.Lock()
+		running := svc.end.IsZero()
+		svc.restarting = running
+		cmd, done := svc.cmd, svc.done
+		svc.mu.Unlock()
+		if running {
+			r.emit(svc.event(ProcessStopping))
+			stopGracefully(cmd.Process, done, stopTimeout)
+		}
+		if svc.stopped.Load() || ctx.Err() != nil {
+			return
+		}
+		svc.mu.Lock()
+		svc.restarts++
+		svc.restarting, svc.failed = false, false
+		svc.mu.Unlock()
+		if err := launch(svc); err != nil {
+			svc.mu.Lock()
+			svc.end, svc.err, svc.failed = time.Now(), err, true
+			svc.mu.Unlock()
+			r.say(svc, true, fmt.Sprintf("%s%v\n", prefix, err))
+			return
+		}
+		r.emit(svc.event(ProcessReady))
+	}
 
-		for _, cmdStr := range group.Commands {
-			parts := splitFields(cmdStr)
-			if len(parts) == 0 {
-				continue
-			}
+	sess.restart = restart
+	sess.ending = quitting
 
-			cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
-			if group.AbsolutePath != "" {
-				cmd.Dir = group.AbsolutePath
+	// quit stops every service gracefully, in parallel, and ends the run as
+	// if they had exited on their own.
+	sess.quit = func() {
+		stopOnce.Do(func() {
+			var stopping sync.WaitGroup
+			for _, s := range sess.services() {
+				if s.stopWatching != nil {
+					s.stopWatching()
+				}
+				s.mu.Lock()
+				running := !s.oneshot && !s.start.IsZero() && s.end.IsZero()
+				cmd, done := s.cmd, s.done
+				s.mu.Unlock()
+				if running {
+					s.stopped.Store(true)
+					r.emit(s.event(ProcessStopping))
+					stopping.Add(1)
+					go func() {
+						defer stopping.Done()
+						stopGracefully(cmd.Process, done, stopTimeout)
+					}()
+				}
 			}
+			stopping.Wait()
+			close(stopCh)
+		})
+		// Interrupt a oneshot or hook that is still starting the project.
+		cancel()
+	}
 
-			stdoutPipe, err := cmd.StdoutPipe()
-			if err != nil {
-				// immediate failure to obtain pipe
-				_ = r.shutdownAll()
-				return fmt.Errorf("failed to obtain stdout pipe for %q: %w", cmdStr, err)
-			}
-			stderrPipe, err := cmd.StderrPipe()
-			if err != nil {
-				_ = r.shutdownAll()
-				return fmt.Errorf("failed to obtain stderr pipe for %q: %w", cmdStr, err)
-			}
+	// startGroups starts the groups sequentially, running their hooks.
+	startGroups := func() error {
+		if err := r.runHooks(ctx, projScope, "before", proj.Before); err != nil {
+			return err
+		}
+		for gi, group := range proj.Groups {
+			r.emit(Event{Kind: GroupStarting, Group: group.Name, Dir: group.AbsolutePath})
 
-			if err := cmd.Start(); err != nil {
-				_ = r.shutdownAll()
-				return fmt.Errorf("failed to start command %q: %w", cmdStr, err)
+			scope := groupScopes[gi]
+			if err := r.runHooks(ctx, scope, "before", group.Before); err != nil {
+				return err
 			}
+			started = gi + 1
 
-			// record process for later shutdown
-			r.addProc(cmd)
+			// si counts the group's services, to find the stdin target.
+			si := 0
+			for _, c := range setupFirst(group.Commands) {
+				cmdStr := c.Command
+				parts := splitFields(cmdStr)
+				if len(parts) == 0 {
+					continue
+				}
 
-			// prefix output with group name
-			prefix := fmt.Sprintf("[%s] ", group.Name)
-			startStream(stdoutPipe, os.Stdout, prefix)
-			startStream(stderrPipe, os.Stderr, prefix)
+				if err := r.runHooks(ctx, hookScope{name: projScope.name, dir: scope.dir, env: scope.env, policy: projScope.policy}, "beforeEach", proj.BeforeEach); err != nil {
+					return err
+				}
+				if err := r.runHooks(ctx, scope, "beforeEach", group.BeforeEach); err != nil {
+					return err
+				}
 
-			// wait for process in background
-			wg.Add(1)
-			go func(cmd *exec.Cmd, desc string) {
-				defer wg.Done()
-				if err := cmd.Wait(); err != nil {
-					// Try to send the first error observed; do not block if channel already has an error.
-					select {
-					case errCh <- fmt.Errorf("process %q exited with error: %w", desc, err):
-						// cancel context so other processes get signaled
-						cancel()
-						// attempt to shutdown remaining processes
-						_ = r.shutdownAll()
-					default:
-						// already have an error, nothing to do
+				if quitting() {
+					return nil
+				}
+				svc := &service{group: group.Name, gi: gi, command: cmdStr, oneshot: c.Oneshot(), tty: c.TTY}
+				if !svc.oneshot {
+					svc.target = gi == targetGroup && si == targetService
+					svc.input = c.Stdin || c.TTY || svc.target
+					si++
+				}
+				track(gi, svc)
+				if svc.oneshot {
+					if err := r.runOneshot(ctx, group, svc); err != nil {
+						return err
 					}
+					continue
 				}
-			}(cmd, strings.Join(parts, " "))
-		}
 
-		fmt.Printf("[%s] Started\n\n", group.Name)
+				var w *watch.Watcher
+				watchCtx, stopWatching := context.WithCancel(ctx)
+				svc.stopWatching = stopWatching
+				if c.Watch != nil {
+					if w, err = watcherFor(group, c.Watch); err != nil {
+						return &exitcode.ConfigError{Err: err}
+					}
+				}
+				if err := launch(svc); err != nil {
+					svc.mu.Lock()
+					svc.err, svc.failed = err, true
+					svc.mu.Unlock()
+					return err
+				}
+				if w != nil {
+					wg.Add(1)
+					go func(svc *service) {
+						defer wg.Done()
+						_ = w.Run(watchCtx, func(files []string) {
+							restart(svc, describeChange(files))
+						})
+					}(svc)
+				}
+			}
+
+			for _, s := range sess.services() {
+				if s.gi == gi && !s.oneshot {
+					r.emit(s.event(ProcessReady))
+				}
+			}
+			r.emit(Event{Kind: GroupStarted, Group: group.Name})
+			if err := r.runHooks(ctx, scope, "after", group.After); err != nil {
+				return err
+			}
+		}
+		return r.runHooks(ctx, projScope, "after", proj.After)
+	}
+	if err := startGroups(); err != nil && !quitting() {
+		// A failed process cancels the startup; report it rather than
+		// the step it interrupted.
+		select {
+		case e := <-errCh:
+			return e
+		default:
+			return err
+		}
 	}
 
 	// Wait for all processes to finish or for an error/cancellation.
@@ -133,22 +456,117 @@ func (r *Runner) Start(ctx context.Context, proj projects.Project) error {
 	}()
 
 	select {
-	case <-ctx.Done():
-		// Context was cancelled (either externally or due to a process error).
+	case <-parent.Done():
+		// Context was cancelled externally (e.g. Ctrl+C).
 		// Return context error if no specific process error was reported.
 		select {
 		case e := <-errCh:
 			return e
 		default:
-			return ctx.Err()
+			return parent.Err()
 		}
 	case e := <-errCh:
-		// First process-level error
+		// First process-level error (onFailure: stop-all)
 		return e
+	case <-stopCh:
+		// A service exited cleanly with onExit: stop-all.
 	case <-doneCh:
-		// All processes exited normally
+		// All processes exited
+	}
+	// Failures tolerated by onFailure: continue/stop-group still fail the run.
+	svcMu.Lock()
+	defer svcMu.Unlock()
+	var failed []*service
+	for _, s := range all {
+		s.mu.Lock()
+		if s.failed {
+			failed = append(failed, s)
+		}
+		s.mu.Unlock()
+	}
+	switch len(failed) {
+	case 0:
 		return nil
+	case 1:
+		return exitcode.NewProcessError(failed[0].name(), failed[0].err)
+	}
+	return fmt.Errorf("%d processes failed; first: %w", len(failed), exitcode.NewProcessError(failed[0].name(), failed[0].err))
+}
+
+// setupFirst orders a group's commands so its oneshots, which must complete
+// before the services start, come first.
+func setupFirst(cmds []projects.Command) []projects.Command {
+	out := make([]projects.Command, 0, len(cmds))
+	for _, c := range cmds {
+		if c.Oneshot() {
+			out = append(out, c)
+		}
+	}
+	for _, c := range cmds {
+		if !c.Oneshot() {
+			out = append(out, c)
+		}
 	}
+	return out
+}
+
+// runOneshot runs a setup command of group to completion, sending its
+// output like the group's services. Anything but a zero exit is an error.
+// Its normal exit does not count as the project ending.
+func (r *Runner) runOneshot(ctx context.Context, group projects.CommandGroup, svc *service) error {
+	cmd := r.command(ctx, group, svc.command)
+	r.emit(svc.event(ProcessStarting))
+	wait, err := r.startProcess(cmd, svc)
+	if err != nil {
+		svc.mu.Lock()
+		svc.err, svc.failed = err, true
+		svc.mu.Unlock()
+		r.emit(svc.exitedEvent(err))
+		return exitcode.NewProcessError(svc.name(), err)
+	}
+	svc.mu.Lock()
+	svc.cmd, svc.start = cmd, time.Now()
+	svc.mu.Unlock()
+	r.emit(svc.event(ProcessStarted))
+	r.addProc(cmd)
+	err = wait()
+	r.removeProc(cmd)
+	svc.mu.Lock()
+	svc.end, svc.err = time.Now(), err
+	svc.mu.Unlock()
+	r.emit(svc.exitedEvent(err))
+	if err != nil {
+		if ctx.Err() != nil {
+			svc.stopped.Store(true)
+			return ctx.Err()
+		}
+		svc.mu.Lock()
+		svc.failed = true
+		svc.mu.Unlock()
+		return exitcode.NewProcessError(svc.name(), err)
+	}
+	r.emit(svc.event(ProcessReady))
+	return nil
+}
+
+// command builds the process for cmdStr in group's directory and
+// environment. It gets a process group of its own, which is killed as a
+// whole when ctx is done: cancelling ctx is the hard stop, stopGracefully
+// the polite one. Wait gives up on output still held open by a leftover
+// child a second after the process has exited.
+func (r *Runner) command(ctx context.Context, group projects.CommandGroup, cmdStr string) *exec.Cmd {
+	parts := splitFields(cmdStr)
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	if group.AbsolutePath != "" {
+		cmd.Dir = group.AbsolutePath
+	}
+	if len(group.Env) > 0 {
+		cmd.Env = append(os.Environ(), envList(group.Env)...)
+	}
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return kill(cmd.Process) }
+	cmd.WaitDelay = time.Second
+	return cmd
 }
 
 // Shutdown attempts to kill all started processes.
@@ -163,6 +581,23 @@ func (r *Runner) addProc(cmd *exec.Cmd) {
 	r.mu.Unlock()
 }
 
+// removeProc forgets cmd once its Wait has returned, so that restarts do
+// not grow the list. A process whose group still has members (children it
+// left behind) is kept for shutdownAll to kill.
+func (r *Runner) removeProc(cmd *exec.Cmd) {
+	if cmd.Process != nil && groupAlive(cmd.Process) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, c := range r.procs {
+		if c == cmd {
+			r.procs = append(r.procs[:i], r.procs[i+1:]...)
+			return
+		}
+	}
+}
+
 // shutdownAll kills all recorded processes and clears the list.
 // It returns the first error encountered while killing processes, if any.
 func (r *Runner) shutdownAll() error {
@@ -177,7 +612,7 @@ func (r *Runner) shutdownAll() error {
 		if cmd == nil || cmd.Process == nil {
 			continue
 		}
-		if err := cmd.Process.Kill(); err != nil {
+		if err := kill(cmd.Process); err != nil && !errors.Is(err, os.ErrProcessDone) {
 			if firstErr == nil {
 				firstErr = fmt.Errorf("failed to kill process %d: %w", cmd.Process.Pid, err)
 			}
@@ -186,7 +621,20 @@ func (r *Runner) shutdownAll() error {
 	return firstErr
 }
 
+// splitFields splits a command line into arguments; see projects.SplitArgs.
+// Start rejects the commands and hooks that do not split before running
+// any, so a malformed one yields no arguments here.
 func splitFields(s string) []string {
-	// Naive splitting; this can be enhanced to handle quotes if needed.
-	return strings.Fields(s)
+	parts, _ := projects.SplitArgs(s)
+	return parts
+}
+
+// envList converts an env map into sorted KEY=VALUE pairs for exec.Cmd.Env.
+func envList(env map[string]string) []string {
+	out := make([]string, 0, len(env))
+	for k, v := range env {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
 }
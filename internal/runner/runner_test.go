@@ -0,0 +1,126 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// TestProcessesDuringOneshot reads the process list while oneshots run,
+// fail and fail to start; run it with -race.
+func TestProcessesDuringOneshot(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	tests := []struct {
+		name    string
+		command string
+		state   string
+		failed  bool
+	}{
+		{name: "completes", command: "sh sleep.sh", state: "completed"},
+		{name: "fails", command: "sh fail.sh", state: "exited 3", failed: true},
+		{name: "cannot start", command: "vunat-test-no-such-command", state: "failed to start", failed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeScript(t, dir, "sleep.sh", "sleep 0.2")
+			writeScript(t, dir, "fail.sh", "sleep 0.2; exit 3")
+			proj := projects.Project{Groups: []projects.CommandGroup{{
+				Name:         "setup",
+				AbsolutePath: dir,
+				Commands:     []projects.Command{{Command: tt.command, Kind: projects.KindOneshot}},
+			}}}
+			r := New()
+			done := make(chan error, 1)
+			go func() { done <- r.Start(context.Background(), proj) }()
+
+			timeout := time.After(10 * time.Second)
+			for {
+				select {
+				case err := <-done:
+					if (err != nil) != tt.failed {
+						t.Fatalf("Start() = %v, want failure %v", err, tt.failed)
+					}
+					procs := r.Processes()
+					if len(procs) != 1 || procs[0].State != tt.state {
+						t.Fatalf("processes = %+v, want one in state %q", procs, tt.state)
+					}
+					return
+				case <-timeout:
+					t.Fatal("project did not finish")
+				default:
+				}
+				for _, p := range r.Processes() {
+					_ = p.State
+				}
+				_ = r.WriteStatus(io.Discard)
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}
+}
+
+func writeScript(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestProcsForgotten checks that restarted and finished processes are
+// dropped from the list shutdownAll kills, so that it does not grow with
+// every restart.
+func TestProcsForgotten(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	writeScript(t, dir, "serve.sh", "exec sleep 30")
+	proj := projects.Project{Groups: []projects.CommandGroup{{
+		Name:         "web",
+		AbsolutePath: dir,
+		Commands: []projects.Command{
+			{Command: "sh -c true", Kind: projects.KindOneshot},
+			{Command: "sh serve.sh"},
+		},
+	}}}
+	r := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- r.Start(ctx, proj) }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, err := r.session(); err == nil && r.Processes()[1].State == "running" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("processes = %+v, want the service running", r.Processes())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for i := 0; i < 3; i++ {
+		if err := r.Restart(2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r.mu.Lock()
+	n := len(r.procs)
+	r.mu.Unlock()
+	if n != 1 {
+		t.Errorf("%d recorded processes after 3 restarts, want 1", n)
+	}
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+}
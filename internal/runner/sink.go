@@ -0,0 +1,223 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Sink receives the events of the projects a Runner starts, to show or
+// record them. A Runner sends each event to all of its sinks; Event is
+// called from the goroutine that observed the event, so it must be fast and
+// safe for concurrent use.
+type Sink interface {
+	Event(e Event)
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(e Event)
+
+// Event calls f(e).
+func (f SinkFunc) Event(e Event) { f(e) }
+
+// SyncWriter serializes the writes to an io.Writer. The sinks write whole
+// lines at once, so sharing one SyncWriter between everything that writes
+// to, say, the terminal keeps lines from being interleaved mid-line.
+type SyncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewSyncWriter returns a SyncWriter writing to w.
+func NewSyncWriter(w io.Writer) *SyncWriter {
+	return &SyncWriter{w: w}
+}
+
+func (s *SyncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// Printer writes events as the familiar terminal output of vunat start:
+// process output and runner messages prefixed with the group name, with
+// errors on the error stream. Pointed at a file it makes a plain-text log.
+type Printer struct {
+	stdout, stderr io.Writer
+
+	mu     sync.Mutex
+	hidden map[string]bool
+}
+
+// NewPrinter returns a Printer writing to stdout and stderr, which may be
+// the same writer.
+func NewPrinter(stdout, stderr io.Writer) *Printer {
+	return &Printer{stdout: stdout, stderr: stderr, hidden: make(map[string]bool)}
+}
+
+// Event writes e, if it is shown at all.
+func (p *Printer) Event(e Event) {
+	prefix := fmt.Sprintf("[%s] ", e.Group)
+	var text string
+	switch e.Kind {
+	case ProcessOutput:
+		text = prefix + e.Line + continuation(e) + "\n"
+	case Message:
+		text = e.Line + continuation(e) + "\n"
+	case GroupStarting:
+		text = fmt.Sprintf("%sStarting in: %s\n", prefix, e.Dir)
+	case GroupStarted:
+		text = prefix + "Started\n\n"
+	case ProcessReady:
+		if !e.Oneshot {
+			return
+		}
+		text = fmt.Sprintf("%s%s completed\n", prefix, e.Command)
+	case ProcessRestarting:
+		text = fmt.Sprintf("%s%s; restarting %s\n", prefix, e.Line, e.Command)
+	default:
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e.Kind == ProcessOutput && p.hidden[e.Group] {
+		return
+	}
+	out := p.stdout
+	if e.Stream == "stderr" {
+		out = p.stderr
+	}
+	_, _ = io.WriteString(out, text)
+}
+
+// continuation returns the marker ending a continued chunk of a line.
+func continuation(e Event) string {
+	if e.Continued {
+		return ContinuationMarker
+	}
+	return ""
+}
+
+// ToggleGroup hides the process output of group, or shows it again, and
+// reports whether it is now hidden.
+func (p *Printer) ToggleGroup(group string) (hidden bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hidden[group] = !p.hidden[group]
+	return p.hidden[group]
+}
+
+// JSONLines writes each event as a JSON object on a line of its own. An
+// output line becomes
+//
+//	{"ts":"…","project":"…","group":"…","command":"…","pid":1234,"stream":"stdout","line":"…"}
+//
+// with "continued":true added to each chunk but the last of a line too long
+// to pass whole. Every other event is a record with an "event" field naming
+// its kind, plus "code" and "error" for an exit, "reason" for a restart,
+// "dir" for a starting group and "stream" and "line" for a message.
+type JSONLines struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLines returns a JSONLines sink writing to w.
+func NewJSONLines(w io.Writer) *JSONLines {
+	return &JSONLines{w: w}
+}
+
+type outputRecord struct {
+	TS        string `json:"ts"`
+	Project   string `json:"project"`
+	Group     string `json:"group"`
+	Command   string `json:"command"`
+	PID       int    `json:"pid"`
+	Stream    string `json:"stream"`
+	Line      string `json:"line"`
+	Continued bool   `json:"continued,omitempty"`
+}
+
+type eventRecord struct {
+	TS      string `json:"ts"`
+	Event   string `json:"event"`
+	Project string `json:"project"`
+	Group   string `json:"group,omitempty"`
+	Command string `json:"command,omitempty"`
+	PID     int    `json:"pid,omitempty"`
+	Code    *int   `json:"code,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Dir     string `json:"dir,omitempty"`
+	Stream  string `json:"stream,omitempty"`
+	Line    string `json:"line,omitempty"`
+}
+
+// Event writes e as one line.
+func (j *JSONLines) Event(e Event) {
+	ts := e.Time.UTC().Format(time.RFC3339Nano)
+	var rec any
+	if e.Kind == ProcessOutput {
+		rec = outputRecord{TS: ts, Project: e.Project, Group: e.Group, Command: e.Command, PID: e.PID, Stream: e.Stream, Line: e.Line, Continued: e.Continued}
+	} else {
+		r := eventRecord{TS: ts, Event: string(e.Kind), Project: e.Project, Group: e.Group, Command: e.Command, PID: e.PID}
+		switch e.Kind {
+		case ProcessExited:
+			code := e.ExitCode
+			r.Code = &code
+			if e.Err != nil {
+				r.Error = e.Err.Error()
+			}
+		case ProcessRestarting:
+			r.Reason = e.Line
+		case GroupStarting:
+			r.Dir = e.Dir
+		case Message:
+			r.Stream, r.Line = e.Stream, e.Line
+		}
+		rec = r
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, _ = j.w.Write(append(b, '\n'))
+}
+
+// Buffer keeps the events it receives in memory, e.g. for a view that
+// shows them later or for checking what a run did.
+type Buffer struct {
+	mu     sync.Mutex
+	limit  int
+	events []Event
+}
+
+// NewBuffer returns a Buffer keeping the last limit events, or all of them
+// if limit is 0.
+func NewBuffer(limit int) *Buffer {
+	return &Buffer{limit: limit}
+}
+
+// Event records e.
+func (b *Buffer) Event(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.events = append(b.events, e)
+	if b.limit > 0 && len(b.events) > 2*b.limit {
+		b.events = append([]Event(nil), b.events[len(b.events)-b.limit:]...)
+	}
+}
+
+// Events returns the recorded events, oldest first.
+func (b *Buffer) Events() []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := b.events
+	if b.limit > 0 && len(events) > b.limit {
+		events = events[len(events)-b.limit:]
+	}
+	return append([]Event(nil), events...)
+}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJSONLines(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 500, time.FixedZone("CEST", 2*60*60))
+	events := []Event{
+		{Kind: GroupStarting, Project: "shop", Group: "api", Dir: "/srv/shop/api"},
+		{Kind: ProcessStarted, Project: "shop", Group: "api", Process: 1, Command: "go run .", PID: 42},
+		{Kind: ProcessOutput, Project: "shop", Group: "api", Process: 1, Command: "go run .", PID: 42, Stream: "stdout", Line: `listening on "8080"`},
+		{Kind: ProcessOutput, Project: "shop", Group: "api", Process: 1, Command: "go run .", PID: 42, Stream: "stderr", Line: "abc", Continued: true},
+		{Kind: ProcessRestarting, Project: "shop", Group: "api", Process: 1, Command: "go run .", PID: 42, Line: "main.go changed"},
+		{Kind: ProcessExited, Project: "shop", Group: "api", Process: 1, Command: "go run .", PID: 42, ExitCode: 2, Err: errors.New("exit status 2")},
+		{Kind: ProcessExited, Project: "shop", Group: "api", Process: 1, Command: "go run .", PID: 43},
+		{Kind: Message, Project: "shop", Group: "api", Stream: "stderr", Line: "[api:before] done"},
+	}
+	const want = `{"ts":"2024-05-01T10:00:00.0000005Z","event":"group-starting","project":"shop","group":"api","dir":"/srv/shop/api"}
+{"ts":"2024-05-01T10:00:00.0000005Z","event":"started","project":"shop","group":"api","command":"go run .","pid":42}
+{"ts":"2024-05-01T10:00:00.0000005Z","project":"shop","group":"api","command":"go run .","pid":42,"stream":"stdout","line":"listening on \"8080\""}
+{"ts":"2024-05-01T10:00:00.0000005Z","project":"shop","group":"api","command":"go run .","pid":42,"stream":"stderr","line":"abc","continued":true}
+{"ts":"2024-05-01T10:00:00.0000005Z","event":"restarting","project":"shop","group":"api","command":"go run .","pid":42,"reason":"main.go changed"}
+{"ts":"2024-05-01T10:00:00.0000005Z","event":"exited","project":"shop","group":"api","command":"go run .","pid":42,"code":2,"error":"exit status 2"}
+{"ts":"2024-05-01T10:00:00.0000005Z","event":"exited","project":"shop","group":"api","command":"go run .","pid":43,"code":0}
+{"ts":"2024-05-01T10:00:00.0000005Z","event":"message","project":"shop","group":"api","stream":"stderr","line":"[api:before] done"}
+`
+	var b strings.Builder
+	j := NewJSONLines(&b)
+	for _, e := range events {
+		e.Time = ts
+		j.Event(e)
+	}
+	if b.String() != want {
+		t.Errorf("JSONLines wrote\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+// TestEmitConcurrent emits long lines from many goroutines at once and
+// checks that every sink wrote each of them whole, on a line of its own.
+func TestEmitConcurrent(t *testing.T) {
+	var jsonOut, textOut bytes.Buffer
+	buf := NewBuffer(0)
+	r := New(NewJSONLines(&jsonOut), NewPrinter(&textOut, &textOut), buf)
+	const writers, lines = 8, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			group := fmt.Sprintf("g%d", w)
+			for i := 0; i < lines; i++ {
+				r.emit(Event{Kind: ProcessOutput, Group: group, Stream: "stdout", Line: strings.Repeat(group, 1000)})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(buf.Events()); n != writers*lines {
+		t.Errorf("buffer has %d events, want %d", n, writers*lines)
+	}
+	jsonLines := strings.Split(strings.TrimSuffix(jsonOut.String(), "\n"), "\n")
+	if len(jsonLines) != writers*lines {
+		t.Fatalf("JSONLines wrote %d lines, want %d", len(jsonLines), writers*lines)
+	}
+	for _, line := range jsonLines {
+		var rec outputRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil || rec.Line != strings.Repeat(rec.Group, 1000) {
+			t.Fatalf("JSONLines wrote a mixed line %.80q (%v)", line, err)
+		}
+	}
+	textLines := strings.Split(strings.TrimSuffix(textOut.String(), "\n"), "\n")
+	if len(textLines) != writers*lines {
+		t.Fatalf("Printer wrote %d lines, want %d", len(textLines), writers*lines)
+	}
+	for _, line := range textLines {
+		group, text, ok := strings.Cut(strings.TrimPrefix(line, "["), "] ")
+		if !ok || text != strings.Repeat(group, 1000) {
+			t.Fatalf("Printer wrote a mixed line %.80q", line)
+		}
+	}
+}
+
+// TestSubscribeFromSink checks that a sink can change the subscriptions
+// from its Event without deadlocking.
+func TestSubscribeFromSink(t *testing.T) {
+	r := New()
+	late := NewBuffer(0)
+	var unsubscribe func()
+	var calls int
+	unsubscribe = r.Subscribe(SinkFunc(func(e Event) {
+		calls++
+		unsubscribe()
+		r.Subscribe(late)
+	}))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.emit(Event{Kind: Message, Line: "one"})
+		r.emit(Event{Kind: Message, Line: "two"})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("emit deadlocked")
+	}
+	if calls != 1 {
+		t.Errorf("unsubscribed sink got %d events, want 1", calls)
+	}
+	if got := late.Events(); len(got) != 1 || got[0].Line != "two" {
+		t.Errorf("sink subscribed during an event got %+v, want only the next event", got)
+	}
+}
@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/tanuvnair/vunat-cli/internal/exitcode"
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+)
+
+// RunTask runs a one-off task of proj in the foreground, connected to the
+// terminal, with extra appended to its arguments. The task runs in the
+// directory and environment of its group (the project's first group by
+// default). It blocks until the task exits; a non-zero exit is returned as
+// an error wrapping *exec.ExitError so callers can propagate the status.
+func (r *Runner) RunTask(ctx context.Context, proj projects.Project, task projects.Task, extra []string) error {
+	parts, err := projects.SplitArgs(task.Command)
+	if err != nil {
+		return &exitcode.ConfigError{Err: err}
+	}
+	parts = append(parts, extra...)
+	if len(parts) == 0 {
+		return fmt.Errorf("task has no command")
+	}
+
+	var group projects.CommandGroup
+	if task.Group != "" {
+		g, ok := proj.Group(task.Group)
+		if !ok {
+			return &exitcode.ConfigError{Err: fmt.Errorf("task refers to unknown group %q", task.Group)}
+		}
+		group = g
+	} else if len(proj.Groups) > 0 {
+		group = proj.Groups[0]
+	}
+
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	cmd.Dir = group.AbsolutePath
+	if task.AbsolutePath != "" {
+		cmd.Dir = task.AbsolutePath
+	}
+	if len(group.Env) > 0 || len(task.Env) > 0 {
+		env := make(map[string]string, len(group.Env)+len(task.Env))
+		for k, v := range group.Env {
+			env[k] = v
+		}
+		for k, v := range task.Env {
+			env[k] = v
+		}
+		cmd.Env = append(os.Environ(), envList(env)...)
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("task %q exited with error: %w", task.Command, err)
+		}
+		return fmt.Errorf("failed to run task %q: %w", task.Command, err)
+	}
+	return nil
+}
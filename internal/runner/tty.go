@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/tanuvnair/vunat-cli/internal/term"
+)
+
+// Default size of a pseudo-terminal until SetTerminalSize is called.
+const (
+	defaultCols = 80
+	defaultRows = 24
+)
+
+// startProcess starts cmd for svc with its output passed to subscribers
+// line by line, under a pseudo-terminal if svc asks for one and pipes
+// otherwise. wait waits for the process and for its output to be read to
+// the end.
+func (r *Runner) startProcess(cmd *exec.Cmd, svc *service) (wait func() error, err error) {
+	stdout := &lineWriter{line: r.lineEmitter(svc, "stdout")}
+	stderr := &lineWriter{line: r.lineEmitter(svc, "stderr")}
+	if svc.tty {
+		wait, err := r.startTTY(cmd, svc, stdout)
+		if err == nil || cmd.Process != nil {
+			return wait, err
+		}
+		r.say(svc, true, fmt.Sprintf("[%s] %s: no pseudo-terminal (%v); using pipes\n", svc.group, svc.command, err))
+	}
+	// Wait returns once the output has been read to the end, so no line
+	// is lost when the process exits.
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	var in io.WriteCloser
+	if svc.input {
+		if in, err = cmd.StdinPipe(); err != nil {
+			return nil, err
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	svc.mu.Lock()
+	svc.stdin = in
+	svc.mu.Unlock()
+	return func() error {
+		err := cmd.Wait()
+		svc.mu.Lock()
+		svc.stdin = nil
+		svc.mu.Unlock()
+		stdout.Flush()
+		stderr.Flush()
+		return err
+	}, nil
+}
+
+// startTTY starts cmd on a new pseudo-terminal whose output goes to out.
+// It fails with cmd.Process unset if no pseudo-terminal can be opened or
+// the process cannot be started on it; cmd is then set up as it was, so
+// that it can still be started with pipes.
+func (r *Runner) startTTY(cmd *exec.Cmd, svc *service, out *lineWriter) (wait func() error, err error) {
+	pty, tty, err := term.OpenPTY()
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	cols, rows := r.cols, r.rows
+	r.mu.Unlock()
+	_ = term.SetSize(pty, cols, rows)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	setControllingTerminal(cmd)
+	err = cmd.Start()
+	// The process has its own copy now; the read below ends once it and
+	// its children have closed theirs.
+	tty.Close()
+	if err != nil {
+		pty.Close()
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+		setProcessGroup(cmd)
+		return nil, err
+	}
+	svc.mu.Lock()
+	svc.pty = pty
+	svc.mu.Unlock()
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		// Reading fails (EIO on Linux) once the terminal is closed.
+		_, _ = io.Copy(out, pty)
+	}()
+	return func() error {
+		err := cmd.Wait()
+		select {
+		case <-copied:
+		case <-time.After(cmd.WaitDelay):
+			// A leftover child still holds the terminal.
+		}
+		svc.mu.Lock()
+		svc.pty = nil
+		svc.mu.Unlock()
+		pty.Close()
+		<-copied
+		out.Flush()
+		return err
+	}, nil
+}
+
+// SetTerminalSize sets the size of the pseudo-terminals of the tty commands
+// of the running project, and of those started later, normally to the size
+// of the terminal vunat runs in.
+func (r *Runner) SetTerminalSize(cols, rows int) {
+	if cols <= 0 || rows <= 0 {
+		return
+	}
+	r.mu.Lock()
+	r.cols, r.rows = cols, rows
+	s := r.sess
+	r.mu.Unlock()
+	if s == nil {
+		return
+	}
+	for _, svc := range s.services() {
+		svc.mu.Lock()
+		if svc.pty != nil {
+			_ = term.SetSize(svc.pty, cols, rows)
+		}
+		svc.mu.Unlock()
+	}
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package runner
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestStartTTYFailure checks that a command which fails to start on a
+// pseudo-terminal is left ready to be started with pipes, without a
+// session or controlling terminal of its own.
+func TestStartTTYFailure(t *testing.T) {
+	cmd := exec.Command("true")
+	cmd.Dir = filepath.Join(t.TempDir(), "missing")
+	setProcessGroup(cmd)
+	svc := &service{group: "app", command: "true", tty: true}
+	if _, err := New().startTTY(cmd, svc, &lineWriter{line: func(string, bool) {}}); err == nil {
+		t.Fatal("startTTY() = nil, want an error")
+	}
+	if cmd.Process != nil {
+		t.Fatal("startTTY() failed with the process started")
+	}
+	if cmd.Stdin != nil || cmd.Stdout != nil || cmd.Stderr != nil {
+		t.Errorf("stdio left set: %v, %v, %v", cmd.Stdin, cmd.Stdout, cmd.Stderr)
+	}
+	if a := cmd.SysProcAttr; a == nil || !a.Setpgid || a.Setsid || a.Setctty {
+		t.Errorf("SysProcAttr = %+v, want a process group only", a)
+	}
+}
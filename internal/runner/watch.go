@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tanuvnair/vunat-cli/internal/projects"
+	"github.com/tanuvnair/vunat-cli/internal/watch"
+)
+
+// watcherFor returns the file watcher for a service of group with the
+// watch settings w.
+func watcherFor(group projects.CommandGroup, w *projects.Watch) (*watch.Watcher, error) {
+	opts := watch.Options{Root: group.AbsolutePath, Paths: w.Paths, Include: w.Include, Exclude: w.Exclude}
+	if w.Debounce != "" {
+		d, err := time.ParseDuration(w.Debounce)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid debounce %q: expected a duration such as \"500ms\"", w.Debounce)
+		}
+		opts.Debounce = d
+	}
+	return watch.New(opts)
+}
+
+// describeChange names the file that triggered a restart, e.g.
+// "cmd/api/main.go changed" or "cmd/api/main.go and 2 more files changed".
+func describeChange(files []string) string {
+	switch len(files) {
+	case 0:
+		return "files changed"
+	case 1:
+		return files[0] + " changed"
+	case 2:
+		return fmt.Sprintf("%s and 1 more file changed", files[0])
+	}
+	return fmt.Sprintf("%s and %d more files changed", files[0], len(files)-1)
+}
@@ -0,0 +1,100 @@
+// Package suggest implements the fuzzy matching used for "did you mean"
+// hints and unique-prefix resolution of command and project names.
+package suggest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Distance returns the Levenshtein edit distance between a and b, counting an
+// adjacent transposition ("strat" vs "start") as a single edit.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// prev2, prev and cur are consecutive rows of the DP table.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+// Similar returns the candidates close to name, best first: those within an
+// edit distance of roughly a third of name's length, plus those that start
+// with name. Matching is case-insensitive.
+func Similar(name string, candidates []string) []string {
+	lower := strings.ToLower(name)
+	maxDist := max(1, len([]rune(name))/3)
+	type match struct {
+		s    string
+		dist int
+	}
+	var matches []match
+	for _, c := range candidates {
+		lc := strings.ToLower(c)
+		d := Distance(lower, lc)
+		if d <= maxDist || (lower != "" && strings.HasPrefix(lc, lower)) {
+			matches = append(matches, match{c, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].dist != matches[j].dist {
+			return matches[i].dist < matches[j].dist
+		}
+		return matches[i].s < matches[j].s
+	})
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m.s)
+	}
+	return out
+}
+
+// Prefix returns the candidates that start with prefix, sorted.
+func Prefix(prefix string, candidates []string) []string {
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Hint formats a "did you mean" suffix for an error message, e.g.
+// `, did you mean "start"?`, or "" when there are no suggestions. At most
+// three suggestions are listed.
+func Hint(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	if len(suggestions) > 3 {
+		suggestions = suggestions[:3]
+	}
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	if len(quoted) == 1 {
+		return ", did you mean " + quoted[0] + "?"
+	}
+	return ", did you mean one of " + strings.Join(quoted, ", ") + "?"
+}
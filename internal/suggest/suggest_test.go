@@ -0,0 +1,84 @@
+package suggest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"start", "start", 0},
+		{"strat", "start", 1},
+		{"ab", "ba", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+		{"list", "lsit", 1},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Distance(tt.b, tt.a); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	commands := []string{"start", "stop", "status", "restart", "list", "lint"}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"strat", []string{"start"}},
+		{"st", []string{"stop", "start", "status"}},
+		{"LIST", []string{"list", "lint"}},
+		{"xyz", []string{}},
+	}
+	for _, tt := range tests {
+		if got := Similar(tt.name, commands); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Similar(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	commands := []string{"stop", "start", "list"}
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"st", []string{"start", "stop"}},
+		{"list", []string{"list"}},
+		{"List", nil},
+		{"x", nil},
+	}
+	for _, tt := range tests {
+		if got := Prefix(tt.prefix, commands); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Prefix(%q) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestHint(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"start"}, `, did you mean "start"?`},
+		{[]string{"a", "b"}, `, did you mean one of "a", "b"?`},
+		{[]string{"a", "b", "c", "d"}, `, did you mean one of "a", "b", "c"?`},
+	}
+	for _, tt := range tests {
+		if got := Hint(tt.in); got != tt.want {
+			t.Errorf("Hint(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package term
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// OpenPTY opens a new pseudo-terminal. A process started with tty as its
+// stdin, stdout and stderr writes to pty, which the caller reads, and reads
+// what the caller writes to pty.
+func OpenPTY() (pty, tty *os.File, err error) {
+	pty, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	var name [128]byte
+	err = control(pty, func(fd uintptr) error {
+		if err := ioctl(fd, syscall.TIOCPTYGRANT, nil); err != nil {
+			return err
+		}
+		if err := ioctl(fd, syscall.TIOCPTYUNLK, nil); err != nil {
+			return err
+		}
+		return ioctl(fd, syscall.TIOCPTYGNAME, unsafe.Pointer(&name[0]))
+	})
+	if err == nil {
+		tty, err = os.OpenFile(cString(name[:]), os.O_RDWR|syscall.O_NOCTTY, 0)
+	}
+	if err != nil {
+		pty.Close()
+		return nil, nil, err
+	}
+	return pty, tty, nil
+}
+
+// cString returns the NUL-terminated string in b.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,34 @@
+package term
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// OpenPTY opens a new pseudo-terminal. A process started with tty as its
+// stdin, stdout and stderr writes to pty, which the caller reads, and reads
+// what the caller writes to pty.
+func OpenPTY() (pty, tty *os.File, err error) {
+	pty, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	var n uint32
+	err = control(pty, func(fd uintptr) error {
+		var unlock int32
+		if err := ioctl(fd, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+			return err
+		}
+		return ioctl(fd, syscall.TIOCGPTN, unsafe.Pointer(&n))
+	})
+	if err == nil {
+		tty, err = os.OpenFile("/dev/pts/"+strconv.FormatUint(uint64(n), 10), os.O_RDWR|syscall.O_NOCTTY, 0)
+	}
+	if err != nil {
+		pty.Close()
+		return nil, nil, err
+	}
+	return pty, tty, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package term
+
+import (
+	"errors"
+	"os"
+)
+
+// OpenPTY is only supported on Linux and macOS.
+func OpenPTY() (pty, tty *os.File, err error) {
+	return nil, nil, errors.New("pseudo-terminals are only supported on Linux and macOS")
+}
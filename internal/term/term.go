@@ -0,0 +1,13 @@
+// Package term puts terminals into the modes the interactive parts of
+// vunat need. It supports Linux and the BSDs (including macOS); elsewhere
+// nothing is treated as a terminal.
+package term
+
+import "os"
+
+// Interactive reports whether keyboard controls can be offered: stdin and
+// stdout are terminals and vunat runs in the foreground of stdin's
+// terminal, so reading keys doesn't stop a background job.
+func Interactive(stdin, stdout *os.File) bool {
+	return IsTerminal(stdin) && IsTerminal(stdout) && isForeground(stdin)
+}
@@ -0,0 +1,27 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package term
+
+import (
+	"errors"
+	"os"
+)
+
+var errUnsupported = errors.New("terminal control is not supported on this platform")
+
+// IsTerminal reports false: terminals are not supported on this platform.
+func IsTerminal(f *os.File) bool { return false }
+
+// Cbreak is not supported on this platform.
+func Cbreak(f *os.File) (restore func() error, err error) { return nil, errUnsupported }
+
+// Size is not supported on this platform.
+func Size(f *os.File) (cols, rows int, err error) { return 0, 0, errUnsupported }
+
+// SetSize is not supported on this platform.
+func SetSize(f *os.File, cols, rows int) error { return errUnsupported }
+
+// NotifyResize does nothing on this platform.
+func NotifyResize(c chan<- os.Signal) {}
+
+func isForeground(f *os.File) bool { return false }
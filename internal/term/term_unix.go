@@ -0,0 +1,105 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package term
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// IsTerminal reports whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	_, err := getTermios(f.Fd())
+	return err == nil
+}
+
+// Cbreak switches f to reading single key presses: input is neither line
+// buffered nor echoed, while Ctrl+C still sends SIGINT and output is
+// processed as usual. The returned function restores the previous mode.
+func Cbreak(f *os.File) (restore func() error, err error) {
+	fd := f.Fd()
+	old, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+	t := *old
+	t.Lflag &^= syscall.ECHO | syscall.ICANON
+	t.Cc[syscall.VMIN] = 1
+	t.Cc[syscall.VTIME] = 0
+	if err := setTermios(fd, &t); err != nil {
+		return nil, err
+	}
+	return func() error { return setTermios(fd, old) }, nil
+}
+
+// Size returns the width and height of the terminal f in characters.
+func Size(f *os.File) (cols, rows int, err error) {
+	var ws winsize
+	if err := ioctl(f.Fd(), syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// SetSize sets the width and height of the terminal f, such as the pty
+// of OpenPTY, whose processes are sent SIGWINCH.
+func SetSize(f *os.File, cols, rows int) error {
+	ws := winsize{Col: uint16(cols), Row: uint16(rows)}
+	return control(f, func(fd uintptr) error {
+		return ioctl(fd, syscall.TIOCSWINSZ, unsafe.Pointer(&ws))
+	})
+}
+
+// control runs fn on the descriptor of f without switching f to blocking
+// mode, as f.Fd would, so that closing f still interrupts a pending Read.
+func control(f *os.File, fn func(fd uintptr) error) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ferr error
+	if err := rc.Control(func(fd uintptr) { ferr = fn(fd) }); err != nil {
+		return err
+	}
+	return ferr
+}
+
+// NotifyResize sends to c whenever the terminal is resized (SIGWINCH).
+func NotifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
+
+// isForeground reports whether the calling process belongs to the
+// foreground process group of the terminal f.
+func isForeground(f *os.File) bool {
+	var pgrp int32
+	if err := ioctl(f.Fd(), syscall.TIOCGPGRP, unsafe.Pointer(&pgrp)); err != nil {
+		return false
+	}
+	return int(pgrp) == syscall.Getpgrp()
+}
+
+type winsize struct {
+	Row, Col, Xpixel, Ypixel uint16
+}
+
+func getTermios(fd uintptr) (*syscall.Termios, error) {
+	var t syscall.Termios
+	if err := ioctl(fd, ioctlGetTermios, unsafe.Pointer(&t)); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func setTermios(fd uintptr, t *syscall.Termios) error {
+	return ioctl(fd, ioctlSetTermios, unsafe.Pointer(t))
+}
+
+func ioctl(fd, req uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
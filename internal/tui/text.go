@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// escapes matches ANSI escape sequences: CSI sequences such as colors and
+// cursor movement, OSC sequences such as window titles, and two-character
+// escapes.
+var escapes = regexp.MustCompile(`\x1b(\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[@-Z\\-_])`)
+
+// sanitize makes a line of process output safe to draw: escape sequences
+// and control characters are dropped and tabs expanded, so the text cannot
+// move the cursor or change the screen.
+func sanitize(s string) string {
+	if strings.IndexFunc(s, func(r rune) bool { return r < ' ' || r == 0x7f }) < 0 {
+		return s
+	}
+	s = escapes.ReplaceAllString(s, "")
+	var b strings.Builder
+	col := 0
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			n := 4 - col%4
+			b.WriteString(strings.Repeat(" ", n))
+			col += n
+		case r < ' ' || r == 0x7f || !unicode.IsPrint(r) && r != ' ':
+		default:
+			b.WriteRune(r)
+			col++
+		}
+	}
+	return b.String()
+}
+
+// fit truncates s to width runes, marking a cut with "…", and pads it with
+// spaces to exactly width.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		runes := []rune(s)
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// highlight shows the case-insensitive matches of query in s in reverse
+// video. It leaves s alone if lowercasing would change its byte offsets.
+func highlight(s, query string) string {
+	if query == "" {
+		return s
+	}
+	lower, q := strings.ToLower(s), strings.ToLower(query)
+	if len(lower) != len(s) {
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, q)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		b.WriteString("\x1b[7m" + s[i:i+len(q)] + "\x1b[27m")
+		s, lower = s[i+len(q):], lower[i+len(q):]
+	}
+}
+
+// contains reports whether s contains query, ignoring case.
+func contains(s, query string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(query))
+}
+
+// formatBytes renders a memory size with a binary unit, e.g. "54 MB" or
+// "1.5 GB".
+func formatBytes(n uint64) string {
+	const unit = 1024
+	size, suffix := float64(n), "B"
+	for _, u := range []string{"KB", "MB", "GB", "TB"} {
+		if size < unit {
+			break
+		}
+		size, suffix = size/unit, u
+	}
+	if size >= 10 || suffix == "B" {
+		return strconv.FormatFloat(size, 'f', 0, 64) + " " + suffix
+	}
+	return strings.TrimSuffix(strconv.FormatFloat(size, 'f', 1, 64), ".0") + " " + suffix
+}
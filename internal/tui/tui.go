@@ -0,0 +1,627 @@
+// Package tui implements the full-screen dashboard of `vunat start --ui`: a
+// process list with state, PID, uptime, CPU/memory use and restarts above a
+// scrollable, searchable log pane. It is driven by the runner's events and
+// controls, never by parsing terminal output.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tanuvnair/vunat-cli/internal/procstat"
+	"github.com/tanuvnair/vunat-cli/internal/runner"
+	"github.com/tanuvnair/vunat-cli/internal/term"
+)
+
+// maxLines bounds each log buffer; older lines are dropped.
+const maxLines = 10000
+
+// footer lists the key bindings.
+const footer = "↑/↓ select  r restart  x stop  i send keys  / search  n/N next/prev match  PgUp/PgDn scroll  g/G top/end  q quit"
+
+// Dashboard is the full-screen view of a running project. Create it with New
+// before the project starts so that no event is missed.
+type Dashboard struct {
+	runner  *runner.Runner
+	project string
+	stop    func()
+	// quit receives Quit requests.
+	quit chan struct{}
+
+	mu sync.Mutex
+	// all holds every line; logs holds the lines of each process by number.
+	all   logBuffer
+	logs  map[int]*logBuffer
+	dirty bool
+
+	// View state.
+	cols, rows int
+	// selected is 0 for the combined log or a process number.
+	selected int
+	// scroll is how many lines the log is scrolled up from its end; 0
+	// follows new output.
+	scroll int
+	// search is the active search; typing is the one being entered, nil
+	// when not searching.
+	search string
+	typing *string
+	// notice is shown in place of the footer until noticeUntil.
+	notice      string
+	noticeUntil time.Time
+	usage       map[int]procstat.Usage
+	// focus is the number of the process the keys go to, -1 for the
+	// project's stdin target, 0 while they control the dashboard; last
+	// is the focus before that, for Ctrl+].
+	focus, last int
+	quitting    bool
+	ended       bool
+	endErr      error
+}
+
+// logBuffer is a bounded list of log lines.
+type logBuffer struct {
+	lines []string
+}
+
+func (b *logBuffer) add(line string) {
+	b.lines = append(b.lines, line)
+	if len(b.lines) > 2*maxLines {
+		b.lines = append([]string(nil), b.lines[len(b.lines)-maxLines:]...)
+	}
+}
+
+// New returns a dashboard for project, subscribed to the events of r.
+func New(r *runner.Runner, project string) *Dashboard {
+	d := &Dashboard{runner: r, project: project, logs: make(map[int]*logBuffer), quit: make(chan struct{}, 1)}
+	d.stop = r.Subscribe(runner.SinkFunc(d.event))
+	return d
+}
+
+// Quit does what q does: it stops the project gracefully and closes the
+// dashboard once it has stopped, or at once if it already has.
+func (d *Dashboard) Quit() {
+	select {
+	case d.quit <- struct{}{}:
+	default:
+	}
+}
+
+// FocusInput sends the keys to the process reading vunat's input (see
+// runner.Runner.SetStdinTarget) once it has started.
+func (d *Dashboard) FocusInput() {
+	d.mu.Lock()
+	d.focus = -1
+	d.mu.Unlock()
+}
+
+// event records a runner event in the log buffers.
+func (d *Dashboard) event(e runner.Event) {
+	var line string
+	switch e.Kind {
+	case runner.ProcessOutput:
+		line = sanitize(e.Line)
+		if e.Continued {
+			line += runner.ContinuationMarker
+		}
+	case runner.Message, runner.GroupStarting, runner.GroupStarted:
+		switch e.Kind {
+		case runner.GroupStarting:
+			line = fmt.Sprintf("[%s] Starting in: %s", e.Group, e.Dir)
+		case runner.GroupStarted:
+			line = fmt.Sprintf("[%s] Started", e.Group)
+		default:
+			line = sanitize(e.Line)
+		}
+		d.mu.Lock()
+		d.all.add(line)
+		d.dirty = true
+		d.mu.Unlock()
+		return
+	case runner.ProcessStarted:
+		line = fmt.Sprintf("▸ started (PID %d)", e.PID)
+	case runner.ProcessExited:
+		switch {
+		case e.Err == nil:
+			line = "▸ exited (status 0)"
+		case e.ExitCode >= 0:
+			line = fmt.Sprintf("▸ exited (status %d)", e.ExitCode)
+		default:
+			line = "▸ exited: " + e.Err.Error()
+		}
+	case runner.ProcessReady:
+		if !e.Oneshot {
+			return
+		}
+		line = "▸ completed"
+	case runner.ProcessRestarting:
+		line = "▸ restarting: " + e.Line
+	case runner.ProcessStopping:
+		line = "▸ stopping"
+	default:
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b := d.logs[e.Process]
+	if b == nil {
+		b = &logBuffer{}
+		d.logs[e.Process] = b
+	}
+	b.add(line)
+	d.all.add("[" + e.Group + "] " + line)
+	// Keep a scrolled-up view still while lines arrive.
+	if d.scroll > 0 && (d.selected == 0 || d.selected == e.Process) {
+		d.scroll++
+	}
+	d.dirty = true
+}
+
+// Run shows the dashboard on out, reading keys from in (which the caller
+// has put into cbreak mode), until the project has ended and the user
+// leaves with q, or ctx is done. result delivers the error Start returned;
+// Run returns it.
+func (d *Dashboard) Run(ctx context.Context, in, out *os.File, result <-chan error) error {
+	defer d.stop()
+	// Alternate screen, hidden cursor; restored on return.
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan string)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := in.Read(buf)
+			if err != nil {
+				return
+			}
+			for _, k := range splitKeys(string(buf[:n])) {
+				select {
+				case keys <- k:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	resize := make(chan os.Signal, 1)
+	term.NotifyResize(resize)
+	defer signal.Stop(resize)
+
+	var sampler procstat.Sampler
+	d.resize(out)
+	d.sample(&sampler)
+	d.draw(out)
+	redraw := time.NewTicker(100 * time.Millisecond)
+	defer redraw.Stop()
+	second := time.NewTicker(time.Second)
+	defer second.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Interrupted: wait for the project to finish stopping.
+			if !d.ended {
+				return <-result
+			}
+			return d.endErr
+		case err := <-result:
+			result = nil
+			d.mu.Lock()
+			d.ended, d.endErr = true, err
+			quitting := d.quitting
+			d.mu.Unlock()
+			if quitting {
+				return err
+			}
+			d.draw(out)
+		case k := <-keys:
+			if d.key(k) {
+				return d.endErr
+			}
+			d.draw(out)
+		case <-d.quit:
+			d.mu.Lock()
+			leave := d.leave()
+			d.mu.Unlock()
+			if leave {
+				return d.endErr
+			}
+			d.draw(out)
+		case <-resize:
+			d.resize(out)
+			d.draw(out)
+		case <-second.C:
+			d.sample(&sampler)
+			d.draw(out)
+		case <-redraw.C:
+			d.mu.Lock()
+			dirty := d.dirty
+			d.mu.Unlock()
+			if dirty {
+				d.draw(out)
+			}
+		}
+	}
+}
+
+func (d *Dashboard) resize(out *os.File) {
+	cols, rows, err := term.Size(out)
+	if err != nil || cols <= 0 || rows <= 0 {
+		cols, rows = 80, 24
+	}
+	d.mu.Lock()
+	d.cols, d.rows = cols, rows
+	d.mu.Unlock()
+}
+
+// sample measures the CPU and memory use of the running processes.
+func (d *Dashboard) sample(s *procstat.Sampler) {
+	var pids []int
+	for _, p := range d.runner.Processes() {
+		if p.State == "running" && p.PID != 0 {
+			pids = append(pids, p.PID)
+		}
+	}
+	usage := s.Sample(pids)
+	d.mu.Lock()
+	d.usage = usage
+	d.mu.Unlock()
+}
+
+// splitKeys splits terminal input into keys: escape sequences such as the
+// arrow keys stay together, everything else is one key per character.
+func splitKeys(s string) []string {
+	var keys []string
+	for len(s) > 0 {
+		if s[0] == 0x1b && len(s) > 2 && (s[1] == '[' || s[1] == 'O') {
+			end := 2
+			for end < len(s) && (s[end] < '@' || s[end] > '~') {
+				end++
+			}
+			if end < len(s) {
+				end++
+			}
+			keys = append(keys, s[:end])
+			s = s[end:]
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s)
+		keys = append(keys, s[:size])
+		s = s[size:]
+	}
+	return keys
+}
+
+// key handles one key and reports whether the dashboard should close.
+func (d *Dashboard) key(k string) bool {
+	if d.forward(k) {
+		return false
+	}
+	// Ask the runner before locking: its events take d.mu.
+	procs := d.runner.Processes()
+	target := d.runner.InputTarget()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.typing != nil {
+		switch k {
+		case "\r", "\n":
+			d.search, d.typing = *d.typing, nil
+			d.jump(-1, true)
+		case "\x1b":
+			d.typing = nil
+		case "\x7f", "\b":
+			if s := *d.typing; s != "" {
+				_, size := utf8.DecodeLastRuneInString(s)
+				*d.typing = s[:len(s)-size]
+			}
+		default:
+			if k[0] >= ' ' && k[0] != 0x7f && k[0] != 0x1b {
+				*d.typing += k
+			}
+		}
+		return false
+	}
+
+	switch k {
+	case "q":
+		return d.leave()
+	case "\x1b[A", "k":
+		if d.selected > 0 {
+			d.selected--
+			d.scroll = 0
+		}
+	case "\x1b[B", "j":
+		if d.selected < len(procs) {
+			d.selected++
+			d.scroll = 0
+		}
+	case "\x1b[5~", "\x02":
+		d.scroll += d.logHeight(len(procs)) - 1
+	case "\x1b[6~", "\x06":
+		d.scroll = max(0, d.scroll-(d.logHeight(len(procs))-1))
+	case "g", "\x1b[H", "\x1b[1~", "\x1bOH":
+		d.scroll = len(d.buffer().lines)
+	case "G", "\x1b[F", "\x1b[4~", "\x1bOF":
+		d.scroll = 0
+	case "/":
+		s := ""
+		d.typing = &s
+	case "n":
+		d.jump(-1, false)
+	case "N":
+		d.jump(1, false)
+	case "\x1b":
+		d.search = ""
+	case "\x1d":
+		if d.last == 0 {
+			d.setNotice("Select a process that reads input and press i.")
+			break
+		}
+		d.focus, d.last = d.last, 0
+	case "i":
+		n := d.selected
+		if n == 0 {
+			n = target
+		}
+		switch {
+		case n == 0 || n > len(procs):
+			d.setNotice("Select a process first (↑/↓).")
+		case !procs[n-1].Input:
+			d.setNotice(fmt.Sprintf("[%s] %s does not read input; set \"stdin\": true or use --attach.", procs[n-1].Group, procs[n-1].Command))
+		default:
+			d.focus, d.last = n, 0
+		}
+	case "r", "x":
+		if d.selected == 0 {
+			d.setNotice("Select a process first (↑/↓).")
+			break
+		}
+		n := d.selected
+		go func() {
+			var err error
+			if k == "r" {
+				err = d.runner.Restart(n)
+			} else {
+				err = d.runner.StopProcess(n)
+			}
+			if err != nil {
+				d.mu.Lock()
+				d.setNotice(err.Error())
+				d.dirty = true
+				d.mu.Unlock()
+			}
+		}()
+	}
+	return false
+}
+
+// forward passes k to the focused process and reports whether it did.
+// Ctrl+] gives the keys back to the dashboard; so do a write error and the
+// end of the project. The
+// runner is called without d.mu held, as a process slow to read its input
+// blocks the write.
+func (d *Dashboard) forward(k string) bool {
+	d.mu.Lock()
+	n := d.focus
+	if d.ended {
+		n = 0
+	}
+	if n != 0 && k == "\x1d" {
+		d.focus, d.last = 0, n
+		d.mu.Unlock()
+		return true
+	}
+	d.mu.Unlock()
+	if n < 0 {
+		n = d.runner.InputTarget()
+	}
+	if n <= 0 {
+		return false
+	}
+	if err := d.runner.WriteInput(n, []byte(k)); err != nil {
+		d.mu.Lock()
+		d.focus, d.last = 0, d.focus
+		d.setNotice(err.Error())
+		d.mu.Unlock()
+	}
+	return true
+}
+
+// leave stops the project, or reports that the dashboard can close as it
+// has already stopped. d.mu must be held.
+func (d *Dashboard) leave() bool {
+	if d.ended {
+		return true
+	}
+	if !d.quitting {
+		d.quitting = true
+		d.setNotice("Stopping all processes… (Ctrl+C to kill them)")
+		go func() { _ = d.runner.Stop() }()
+	}
+	return false
+}
+
+func (d *Dashboard) setNotice(s string) {
+	d.notice, d.noticeUntil = s, time.Now().Add(4*time.Second)
+}
+
+// buffer returns the log of the selected view.
+func (d *Dashboard) buffer() *logBuffer {
+	if d.selected == 0 {
+		return &d.all
+	}
+	if b := d.logs[d.selected]; b != nil {
+		return b
+	}
+	return &logBuffer{}
+}
+
+// jump scrolls to the previous (dir -1, towards older lines) or next
+// (dir 1) line matching the search, starting from the bottom of the view,
+// or from the end of the log when fromEnd is set.
+func (d *Dashboard) jump(dir int, fromEnd bool) {
+	if d.search == "" {
+		return
+	}
+	lines := d.buffer().lines
+	bottom := len(lines) - 1 - d.scroll
+	if fromEnd {
+		bottom, dir = len(lines), -1
+	}
+	for i := bottom + dir; i >= 0 && i < len(lines); i += dir {
+		if contains(lines[i], d.search) {
+			// Put the match on the last line of the view.
+			d.scroll = len(lines) - 1 - i
+			return
+		}
+	}
+	d.setNotice(fmt.Sprintf("No more matches for %q.", d.search))
+}
+
+// tableHeight is the number of rows shown for n processes, including the
+// "all output" row.
+func (d *Dashboard) tableHeight(n int) int {
+	return min(n+1, max(1, d.rows/3))
+}
+
+// logHeight is the number of log lines shown.
+func (d *Dashboard) logHeight(n int) int {
+	// Header, column titles, the log title and the footer.
+	return max(1, d.rows-d.tableHeight(n)-4)
+}
+
+// draw renders the whole screen.
+func (d *Dashboard) draw(out *os.File) {
+	procs := d.runner.Processes()
+	target := d.runner.InputTarget()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dirty = false
+	w := d.cols
+	var lines []string
+
+	running := 0
+	for _, p := range procs {
+		if p.State == "running" {
+			running++
+		}
+	}
+	state := fmt.Sprintf("%d/%d running", running, len(procs))
+	if d.ended {
+		state = "stopped"
+	}
+	title := fmt.Sprintf(" vunat · %s · %s", d.project, state)
+	clock := time.Now().Format("15:04:05") + " "
+	lines = append(lines, "\x1b[7m"+fit(title, w-len(clock))+clock+"\x1b[27m")
+
+	// Process table: fixed columns, the process name takes what is left.
+	const fixed = "  #  %-10s  %7s  %8s  %6s  %8s  %8s"
+	nameWidth := max(10, w-len(fmt.Sprintf(fixed, "", "", "", "", "", ""))-2)
+	header := fmt.Sprintf("  #  %-*s  %-10s  %7s  %8s  %6s  %8s  %8s", nameWidth, "PROCESS", "STATE", "PID", "UPTIME", "CPU", "MEM", "RESTARTS")
+	lines = append(lines, "\x1b[1m"+fit(header, w)+"\x1b[22m")
+	rows := []string{fmt.Sprintf("  -  %-*s", nameWidth, "all output")}
+	for _, p := range procs {
+		pid, uptime, cpu, mem := "-", "-", "-", "-"
+		if p.PID != 0 {
+			pid = strconv.Itoa(p.PID)
+		}
+		if p.State == "running" {
+			uptime = formatUptime(time.Since(p.Started))
+			if u, ok := d.usage[p.PID]; ok {
+				cpu, mem = fmt.Sprintf("%.1f%%", u.CPU), formatBytes(u.Memory)
+			}
+		}
+		name := fit(fmt.Sprintf("[%s] %s", p.Group, sanitize(p.Command)), nameWidth)
+		rows = append(rows, fmt.Sprintf("%3d  %s  %-10s  %7s  %8s  %6s  %8s  %8d", p.Number, name, fit(p.State, 10), pid, uptime, cpu, mem, p.Restarts))
+	}
+	// Scroll the table so the selection stays visible.
+	height := d.tableHeight(len(procs))
+	first := max(0, d.selected-height+1)
+	for i := first; i < first+height && i < len(rows); i++ {
+		row := fit(rows[i], w)
+		if i == d.selected {
+			row = "\x1b[7m" + row + "\x1b[27m"
+		}
+		lines = append(lines, row)
+	}
+
+	// Log pane.
+	buf := d.buffer()
+	logHeight := d.logHeight(len(procs))
+	d.scroll = min(d.scroll, max(0, len(buf.lines)-logHeight))
+	end := len(buf.lines) - d.scroll
+	start := max(0, end-logHeight)
+	name := "all output"
+	if d.selected > 0 && d.selected <= len(procs) {
+		p := procs[d.selected-1]
+		name = fmt.Sprintf("[%s] %s", p.Group, sanitize(p.Command))
+	}
+	logTitle := "── " + name + " "
+	if d.search != "" {
+		logTitle += fmt.Sprintf("─ search: %s ", d.search)
+	}
+	if d.scroll > 0 {
+		logTitle += fmt.Sprintf("─ %d more below ", d.scroll)
+	}
+	lines = append(lines, "\x1b[2m"+fit(logTitle+strings.Repeat("─", max(0, w)), w)+"\x1b[22m")
+	for i := start; i < end; i++ {
+		lines = append(lines, highlight(fit(buf.lines[i], w), d.search))
+	}
+	for i := end - start; i < logHeight; i++ {
+		lines = append(lines, "")
+	}
+
+	// Footer: the search being typed, a notice, or the key bindings.
+	var foot string
+	switch {
+	case d.typing != nil:
+		foot = "/" + *d.typing + "▏"
+	case time.Now().Before(d.noticeUntil):
+		foot = d.notice
+	case d.focus != 0 && !d.ended:
+		n := d.focus
+		if n < 0 {
+			n = target
+		}
+		if n > 0 && n <= len(procs) {
+			foot = fmt.Sprintf("Keys go to [%s] %s — Ctrl+] for the dashboard's keys", procs[n-1].Group, procs[n-1].Command)
+		} else {
+			foot = footer
+		}
+	case d.ended && d.endErr != nil:
+		foot = "Project stopped: " + d.endErr.Error() + " — press q to exit"
+	case d.ended:
+		foot = "Project stopped — press q to exit"
+	default:
+		foot = footer
+	}
+	lines = append(lines, "\x1b[7m"+fit(" "+foot, w)+"\x1b[27m")
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i >= d.rows {
+			break
+		}
+		fmt.Fprintf(&b, "\x1b[%d;1H%s\x1b[K", i+1, line)
+	}
+	_, _ = out.WriteString(b.String())
+}
+
+// formatUptime renders d compactly, e.g. "42s", "3m05s" or "2h07m".
+func formatUptime(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
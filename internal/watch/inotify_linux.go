@@ -0,0 +1,142 @@
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE | syscall.IN_ATTRIB |
+	syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// inotify watches every directory of the roots (the parent directory for a
+// file root) and sends the paths of changed files to events until ctx is
+// done. It returns an error, leaving nothing running, if inotify cannot be
+// set up for all of them.
+func (w *Watcher) inotify(ctx context.Context, events chan<- string) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+	// A non-blocking descriptor makes the file pollable, so Close below
+	// interrupts a pending Read.
+	f := os.NewFile(uintptr(fd), "inotify")
+	in := &inotifyState{fd: fd, dirs: make(map[int32]string)}
+	for _, r := range w.roots {
+		if !r.dir {
+			err = in.add(filepath.Dir(r.path))
+		} else {
+			err = in.addTree(w, r.path)
+		}
+		if err != nil {
+			f.Close()
+			return err
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		f.Close()
+	}()
+	go func() {
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+			for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+				ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+				nameBytes := buf[off+syscall.SizeofInotifyEvent : off+syscall.SizeofInotifyEvent+int(ev.Len)]
+				off += syscall.SizeofInotifyEvent + int(ev.Len)
+
+				dir, ok := in.dir(ev.Wd)
+				switch {
+				case ev.Mask&syscall.IN_Q_OVERFLOW != 0:
+					// Events were lost: report the roots as changed.
+					for _, r := range w.roots {
+						send(ctx, events, r.path)
+					}
+					continue
+				case ev.Mask&syscall.IN_IGNORED != 0:
+					in.remove(ev.Wd)
+					continue
+				case !ok:
+					continue
+				}
+				file := filepath.Join(dir, cString(nameBytes))
+				if !w.inScope(file) {
+					continue
+				}
+				if ev.Mask&syscall.IN_ISDIR != 0 {
+					// A new directory: watch it and report what it already holds.
+					if ev.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 && !w.skipDir(file) {
+						_ = in.addTree(w, file)
+						w.walkDir(file, func(p string, _ fs.FileInfo) { send(ctx, events, p) })
+					}
+					continue
+				}
+				send(ctx, events, file)
+			}
+		}
+	}()
+	return nil
+}
+
+// inotifyState maps watch descriptors to the directories they watch.
+type inotifyState struct {
+	fd   int
+	mu   sync.Mutex
+	dirs map[int32]string
+}
+
+func (in *inotifyState) add(dir string) error {
+	wd, err := syscall.InotifyAddWatch(in.fd, dir, inotifyMask)
+	if err != nil {
+		return err
+	}
+	in.mu.Lock()
+	in.dirs[int32(wd)] = dir
+	in.mu.Unlock()
+	return nil
+}
+
+// addTree watches dir and every directory below it that is not skipped.
+func (in *inotifyState) addTree(w *Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if p != dir && w.skipDir(p) {
+			return filepath.SkipDir
+		}
+		return in.add(p)
+	})
+}
+
+func (in *inotifyState) dir(wd int32) (string, bool) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	dir, ok := in.dirs[wd]
+	return dir, ok
+}
+
+func (in *inotifyState) remove(wd int32) {
+	in.mu.Lock()
+	delete(in.dirs, wd)
+	in.mu.Unlock()
+}
+
+// cString returns the NUL-padded name of an inotify event as a string.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
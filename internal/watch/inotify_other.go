@@ -0,0 +1,13 @@
+//go:build !linux
+
+package watch
+
+import (
+	"context"
+	"errors"
+)
+
+// inotify is only available on Linux; Run polls instead.
+func (w *Watcher) inotify(ctx context.Context, events chan<- string) error {
+	return errors.New("inotify is not supported on this platform")
+}
@@ -0,0 +1,295 @@
+// Package watch reports changed files under a set of paths. It uses inotify
+// on Linux and falls back to polling elsewhere, or when inotify is
+// unavailable (e.g. the watch limit is exhausted).
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultDebounce is how long a burst of changes must settle before it is
+// reported when Options.Debounce is zero.
+const DefaultDebounce = 300 * time.Millisecond
+
+// DefaultInterval is the polling period of the fallback watcher.
+const DefaultInterval = 500 * time.Millisecond
+
+// skipDirs are never descended into: they are large, change constantly and
+// are almost never what a restart should follow.
+var skipDirs = map[string]bool{".git": true, ".hg": true, ".svn": true, "node_modules": true}
+
+// Options configures a Watcher.
+type Options struct {
+	// Root is the directory relative Paths and the glob patterns are
+	// resolved against.
+	Root string
+	// Paths are the files and directories to watch; directories are watched
+	// recursively. Empty means Root itself.
+	Paths []string
+	// Include limits reported files to those matching one of the patterns;
+	// empty means every file. Exclude drops files matching any pattern.
+	// Patterns use path.Match syntax plus "**" for any number of
+	// directories; a pattern without "/" matches the file name anywhere.
+	Include []string
+	Exclude []string
+	// Debounce is how long to wait after the last change before reporting
+	// the burst; zero means DefaultDebounce.
+	Debounce time.Duration
+	// Interval is the period of the polling fallback; zero means
+	// DefaultInterval.
+	Interval time.Duration
+}
+
+// Watcher reports changes under the paths of its Options.
+type Watcher struct {
+	opts  Options
+	roots []root
+}
+
+// root is a watched path: a directory tree or a single file.
+type root struct {
+	path string
+	dir  bool
+}
+
+// New validates opts and resolves its paths. Paths must exist.
+func New(opts Options) (*Watcher, error) {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	for _, p := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := path.Match(strings.ReplaceAll(p, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	paths := opts.Paths
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	w := &Watcher{opts: opts}
+	for _, p := range paths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(opts.Root, p)
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("cannot watch %s: %w", p, err)
+		}
+		w.roots = append(w.roots, root{path: filepath.Clean(p), dir: info.IsDir()})
+	}
+	return w, nil
+}
+
+// Run watches until ctx is done. After each burst of changes has settled it
+// calls onChange with the changed files (relative to Root where possible) in
+// the order they were first seen. onChange runs on Run's goroutine; changes
+// made meanwhile are reported in the next burst.
+func (w *Watcher) Run(ctx context.Context, onChange func(files []string)) error {
+	events := make(chan string, 256)
+	if err := w.inotify(ctx, events); err != nil {
+		go w.poll(ctx, events)
+	}
+
+	timer := time.NewTimer(w.opts.Debounce)
+	timer.Stop()
+	var fire <-chan time.Time
+	var pending []string
+	seen := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case file := <-events:
+			rel := w.rel(file)
+			if !w.accept(rel) {
+				continue
+			}
+			if !seen[rel] {
+				seen[rel] = true
+				pending = append(pending, rel)
+			}
+			timer.Reset(w.opts.Debounce)
+			fire = timer.C
+		case <-fire:
+			fire = nil
+			files := pending
+			pending, seen = nil, make(map[string]bool)
+			onChange(files)
+		}
+	}
+}
+
+// rel returns file relative to Root, or unchanged if it lies outside it.
+func (w *Watcher) rel(file string) string {
+	if r, err := filepath.Rel(w.opts.Root, file); err == nil && !strings.HasPrefix(r, "..") {
+		return filepath.ToSlash(r)
+	}
+	return filepath.ToSlash(file)
+}
+
+// accept reports whether a change to rel should be reported.
+func (w *Watcher) accept(rel string) bool {
+	if len(w.opts.Include) > 0 && !matchAny(w.opts.Include, rel) {
+		return false
+	}
+	return !matchAny(w.opts.Exclude, rel)
+}
+
+// inScope reports whether file is covered by one of the roots: inside a
+// watched directory or one of the watched files.
+func (w *Watcher) inScope(file string) bool {
+	for _, r := range w.roots {
+		if file == r.path {
+			return true
+		}
+		if r.dir && strings.HasPrefix(file, r.path+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipDir reports whether the directory dir (below a root) is not watched.
+func (w *Watcher) skipDir(dir string) bool {
+	if skipDirs[filepath.Base(dir)] {
+		return true
+	}
+	rel := w.rel(dir)
+	for _, p := range w.opts.Exclude {
+		if match(p, rel) || match(strings.TrimSuffix(p, "/**"), rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls fn for every file in the scope of the roots, skipping excluded
+// directories.
+func (w *Watcher) walk(fn func(file string, info fs.FileInfo)) {
+	for _, r := range w.roots {
+		if !r.dir {
+			if info, err := os.Stat(r.path); err == nil {
+				fn(r.path, info)
+			}
+			continue
+		}
+		w.walkDir(r.path, fn)
+	}
+}
+
+// walkDir calls fn for every file below dir, skipping excluded directories.
+func (w *Watcher) walkDir(dir string, fn func(file string, info fs.FileInfo)) {
+	_ = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != dir && w.skipDir(p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			fn(p, info)
+		}
+		return nil
+	})
+}
+
+// poll reports files that appear, disappear or change size or modification
+// time between two scans taken Interval apart.
+func (w *Watcher) poll(ctx context.Context, events chan<- string) {
+	type state struct {
+		size    int64
+		modTime time.Time
+	}
+	scan := func() map[string]state {
+		files := make(map[string]state)
+		w.walk(func(file string, info fs.FileInfo) {
+			files[file] = state{size: info.Size(), modTime: info.ModTime()}
+		})
+		return files
+	}
+	prev := scan()
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		cur := scan()
+		for file, s := range cur {
+			if old, ok := prev[file]; !ok || old != s {
+				send(ctx, events, file)
+			}
+		}
+		for file := range prev {
+			if _, ok := cur[file]; !ok {
+				send(ctx, events, file)
+			}
+		}
+		prev = cur
+	}
+}
+
+func send(ctx context.Context, events chan<- string, file string) {
+	select {
+	case events <- file:
+	case <-ctx.Done():
+	}
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if match(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether the slash-separated path rel matches pattern. A
+// pattern without "/" is matched against the last element only; "**" matches
+// any number of path elements.
+func match(pattern, rel string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchParts(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+func matchParts(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchParts(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
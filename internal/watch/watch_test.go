@@ -0,0 +1,104 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/vunat/main.go", true},
+		{"*.go", "main.go.orig", false},
+		{"./*.go", "cmd/main.go", true},
+		{"src/*.ts", "src/app.ts", true},
+		{"src/*.ts", "src/lib/app.ts", false},
+		{"src/**/*.ts", "src/app.ts", true},
+		{"src/**/*.ts", "src/lib/deep/app.ts", true},
+		{"src/**/*.ts", "test/app.ts", false},
+		{"**/testdata/**", "testdata", true},
+		{"**/testdata/**", "pkg/testdata/a/b.json", true},
+		{"**/testdata/**", "pkg/data/b.json", false},
+		{"dist/**", "dist", true},
+		{"dist/**", "dist/js/app.js", true},
+		{"dist/**", "src/dist/app.js", false},
+		{"a/b", "a/b/c", false},
+		{"a/[bc]/d", "a/c/d", true},
+	}
+	for _, tt := range tests {
+		if got := match(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+// TestRunDebounce writes a burst of changes and checks they are reported
+// together, once, only after the burst has settled for Debounce.
+func TestRunDebounce(t *testing.T) {
+	dir := t.TempDir()
+	const debounce = 300 * time.Millisecond
+	w, err := New(Options{Root: dir, Exclude: []string{"*.log"}, Debounce: debounce, Interval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type burst struct {
+		files []string
+		at    time.Time
+	}
+	bursts := make(chan burst, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = w.Run(ctx, func(files []string) { bursts <- burst{files, time.Now()} })
+	}()
+	// Let the watcher take its first look at the directory.
+	time.Sleep(200 * time.Millisecond)
+
+	write := func(name string) time.Time {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(time.Now().String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return time.Now()
+	}
+	next := func() burst {
+		t.Helper()
+		select {
+		case b := <-bursts:
+			return b
+		case <-time.After(5 * time.Second):
+			t.Fatal("no change reported")
+		}
+		return burst{}
+	}
+
+	var last time.Time
+	for _, name := range []string{"a.txt", "b.txt", "debug.log", "a.txt"} {
+		last = write(name)
+		time.Sleep(30 * time.Millisecond)
+	}
+	got := next()
+	if want := []string{"a.txt", "b.txt"}; !reflect.DeepEqual(got.files, want) {
+		t.Errorf("first burst = %v, want %v", got.files, want)
+	}
+	if d := got.at.Sub(last); d < debounce {
+		t.Errorf("first burst reported %v after the last change, want at least %v", d, debounce)
+	}
+
+	write("b.txt")
+	if got := next(); !reflect.DeepEqual(got.files, []string{"b.txt"}) {
+		t.Errorf("second burst = %v, want [b.txt]", got.files)
+	}
+	select {
+	case b := <-bursts:
+		t.Errorf("unexpected burst %v", b.files)
+	case <-time.After(2 * debounce):
+	}
+}